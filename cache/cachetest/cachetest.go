@@ -0,0 +1,40 @@
+// Package cachetest provides the lifecycle tests shared by the cache
+// providers, so each provider package only asserts what is specific to it.
+//
+// Usage, from a provider test:
+//
+//	func TestClosedCache(t *testing.T) {
+//	    c := redis.New[string, string]()
+//	    cachetest.Closed(t, c)
+//	    // provider-specific assertions: the client is closed, ...
+//	}
+package cachetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache"
+)
+
+// Closed closes c and checks the closed-cache contract: every operation
+// returns cache.ErrClosed without reaching the backend (the GetOrSet setter
+// never runs) and Close is idempotent.
+func Closed(t *testing.T, c cache.Cache[string, string]) {
+	t.Helper()
+
+	require.NoError(t, c.Close())
+
+	_, err := c.Get(t.Context(), "k")
+	require.ErrorIs(t, err, cache.ErrClosed)
+	require.ErrorIs(t, c.Set(t.Context(), "k", "v"), cache.ErrClosed)
+	require.ErrorIs(t, c.Delete(t.Context(), "k"), cache.ErrClosed)
+
+	_, err = c.GetOrSet(t.Context(), "k", func() (string, error) {
+		t.Fatal("setter must not run on a closed cache")
+		return "", nil
+	})
+	require.ErrorIs(t, err, cache.ErrClosed)
+	require.NoError(t, c.Close(), "close must be idempotent")
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"time"
+)
+
+type (
+	// Clock is the time source used by providers for TTL handling and
+	// background sweeps. Inject a FakeClock (WithClock) to make expiry
+	// deterministic in tests.
+	Clock interface {
+		// Now returns the current time.
+		Now() time.Time
+
+		// NewTicker returns a Ticker that fires every d.
+		NewTicker(d time.Duration) Ticker
+	}
+
+	// Ticker is the subset of time.Ticker used by providers.
+	Ticker interface {
+		// C returns the channel on which ticks are delivered.
+		C() <-chan time.Time
+
+		// Stop turns off the ticker.
+		Stop()
+	}
+
+	systemClock struct{}
+
+	systemTicker struct {
+		ticker *time.Ticker
+	}
+)
+
+// SystemClock returns the Clock backed by the time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Now returns time.Now().
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker wraps time.NewTicker.
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{ticker: time.NewTicker(d)}
+}
+
+// C returns the ticker channel.
+func (t systemTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+// Stop stops the ticker.
+func (t systemTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,113 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/guionardo/go/cache"
+)
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	clock := cache.SystemClock()
+	assert.WithinDuration(t, time.Now(), clock.Now(), time.Second)
+
+	ticker := clock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("system ticker did not fire")
+	}
+}
+
+func TestFakeClock(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("now_only_moves_on_advance", func(t *testing.T) {
+		t.Parallel()
+
+		clock := cache.NewFakeClock(start)
+		assert.Equal(t, start, clock.Now())
+
+		clock.Advance(time.Hour)
+		assert.Equal(t, start.Add(time.Hour), clock.Now())
+
+		clock.Set(start)
+		assert.Equal(t, start, clock.Now())
+	})
+
+	t.Run("ticker_fires_when_period_elapses", func(t *testing.T) {
+		t.Parallel()
+
+		clock := cache.NewFakeClock(start)
+		ticker := clock.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		clock.Advance(59 * time.Second)
+		assert.Empty(t, ticker.C())
+
+		clock.Advance(time.Second)
+		assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+	})
+
+	t.Run("ticker_drops_missed_ticks", func(t *testing.T) {
+		t.Parallel()
+
+		clock := cache.NewFakeClock(start)
+		ticker := clock.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		clock.Advance(5 * time.Minute)
+		assert.Len(t, ticker.C(), 1)
+		<-ticker.C()
+
+		clock.Advance(30 * time.Second)
+		assert.Empty(t, ticker.C())
+
+		clock.Advance(30 * time.Second)
+		assert.Len(t, ticker.C(), 1)
+	})
+
+	t.Run("stopped_ticker_does_not_fire", func(t *testing.T) {
+		t.Parallel()
+
+		clock := cache.NewFakeClock(start)
+		ticker := clock.NewTicker(time.Minute)
+		ticker.Stop()
+
+		clock.Advance(time.Hour)
+		assert.Empty(t, ticker.C())
+	})
+
+	t.Run("concurrent_advances_are_not_lost", func(t *testing.T) {
+		t.Parallel()
+
+		const advances = 1000
+
+		clock := cache.NewFakeClock(start)
+
+		var wg sync.WaitGroup
+		for range advances {
+			wg.Go(func() { clock.Advance(time.Second) })
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, start.Add(advances*time.Second), clock.Now())
+	})
+
+	t.Run("non_positive_interval_panics", func(t *testing.T) {
+		t.Parallel()
+
+		clock := cache.NewFakeClock(start)
+		assert.Panics(t, func() { clock.NewTicker(0) })
+	})
+}
@@ -20,6 +20,7 @@
 //	cache/valkey    — Valkey (valkey-go, eager connect)
 //	cache/memcache  — Memcache (gomemcache, lazy connect)
 //	cache/postgres  — PostgreSQL (pgx/v5, pgxpool, eager connect)
+//	cache/fake      — scriptable test double (fault injection, virtual clock, call log)
 //
 // Configuration via functional options:
 //
@@ -30,8 +31,23 @@
 //
 // Sentinel errors (wrapped with provider prefix):
 //
-//	var ErrMiss   = errors.New("cache: key not found")
-//	var ErrClosed = errors.New("cache: cache is closed")
+//	var ErrMiss         = errors.New("cache: key not found")
+//	var ErrClosed       = errors.New("cache: cache is closed")
+//	var ErrCloseTimeout = errors.New("cache: timed out draining in-flight operations")
+//
+// Lifecycle: every provider shares the Lifecycle state machine
+// (open → closing → closed). Close is idempotent, waits for in-flight
+// operations up to the provider close timeout (WithCloseTimeout, default
+// DefaultCloseTimeout) and then releases resources exactly once. Any
+// operation started after Close returns an error wrapping ErrClosed.
+//
+// Time: providers that track TTLs themselves (mem, postgres) read time from an
+// injectable Clock (WithClock, default SystemClock). Tests can drive a
+// FakeClock with Advance to observe expiry and sweeps without sleeping:
+//
+//	clock := cache.NewFakeClock(time.Now())
+//	c := mem.New[string, string](cache.WithClock(clock), cache.WithDefaultTTL(time.Minute))
+//	clock.Advance(time.Minute) // entries set before are now expired
 //
 // Consumer code imports providers at construction time only —
 // the cache.Cache interface is the only type in business logic.
@@ -10,4 +10,8 @@ var (
 
 	// ErrClosed is returned when operations are attempted on a closed cache.
 	ErrClosed = errors.New("cache: cache is closed")
+
+	// ErrCloseTimeout is returned by Close when in-flight operations did not
+	// finish within the close timeout. Resources are released anyway.
+	ErrCloseTimeout = errors.New("cache: timed out draining in-flight operations")
 )
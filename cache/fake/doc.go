@@ -0,0 +1,22 @@
+// Package fake provides a scriptable cache.Cache for testing failure paths.
+//
+// The fake wraps cache/mem and adds:
+//   - Fault injection: errors and/or latency for specific operations and keys,
+//     optionally limited to a number of hits
+//   - A virtual clock: Advance moves time forward so TTL expiry can be observed
+//     without sleeping
+//   - A call log: every operation is recorded with its key, result and
+//     virtual timestamp, for assertions
+//
+// Usage:
+//
+//	c := fake.New[string, string](cache.WithDefaultTTL(time.Minute))
+//	c.InjectError(fake.OpGet, errors.New("boom"), "user:1")
+//	c.InjectLatency(fake.OpAny, 50*time.Millisecond)
+//
+//	_, err := c.Get(ctx, "user:1") // boom
+//	c.Advance(2 * time.Minute)     // entries set before now are expired
+//
+//	c.CallCount(fake.OpGet)        // 1
+//	c.Calls()                      // []fake.Call[string]{...}
+package fake
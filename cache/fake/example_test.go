@@ -0,0 +1,31 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guionardo/go/cache"
+	"github.com/guionardo/go/cache/fake"
+)
+
+func ExampleNew() {
+	ctx := context.Background()
+	c := fake.New[string, string](cache.WithDefaultTTL(time.Minute))
+	c.InjectError(fake.OpGet, errors.New("connection reset"), "flaky")
+
+	_ = c.Set(ctx, "flaky", "v")
+	_, err := c.Get(ctx, "flaky")
+	fmt.Println(err)
+
+	_ = c.Set(ctx, "k", "v")
+	c.Advance(time.Minute)
+	_, err = c.Get(ctx, "k")
+	fmt.Println(errors.Is(err, cache.ErrMiss))
+	fmt.Println(c.CallCount(fake.OpGet))
+	// Output:
+	// cache/fake: connection reset
+	// true
+	// 2
+}
@@ -0,0 +1,306 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guionardo/go/cache"
+	"github.com/guionardo/go/cache/mem"
+)
+
+type (
+	// Cache is a scriptable cache provider implementing cache.Cache[K, V].
+	// Values are stored in a cache/mem instance driven by a cache.FakeClock;
+	// faults and the call log are layered on top.
+	Cache[K comparable, V any] struct {
+		inner *mem.Cache[K, V]
+		clock *cache.FakeClock
+
+		mu     sync.Mutex
+		faults []*fault[K]
+		calls  []Call[K]
+	}
+
+	// Call is one recorded cache operation.
+	Call[K comparable] struct {
+		Op  Op
+		Key K
+		// Err is the error returned to the caller (nil on success).
+		Err error
+		// At is the virtual clock time when the call started.
+		At time.Time
+	}
+)
+
+// New creates a fake cache provider with optional functional options,
+// which are passed on to cache/mem (default TTL is 5 minutes unless overridden).
+//
+// The cache always runs on a cache.FakeClock: pass one with cache.WithClock
+// to share it with other components under test, otherwise a new one starting
+// at time.Now() is used. Any other Clock is ignored.
+func New[K comparable, V any](opts ...cache.Option) *Cache[K, V] {
+	var cfg cache.Config
+	for _, opt := range opts {
+		opt.Apply(&cfg)
+	}
+
+	clock, ok := cfg.Clock.(*cache.FakeClock)
+	if !ok {
+		clock = cache.NewFakeClock(time.Now())
+	}
+
+	return &Cache[K, V]{
+		inner: mem.New[K, V](append(opts, cache.WithClock(clock))...),
+		clock: clock,
+	}
+}
+
+// Inject adds a scripted fault. Faults are evaluated in injection order and
+// every matching fault applies (latencies add up, the first error wins).
+func (c *Cache[K, V]) Inject(f Fault[K]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults = append(c.faults, &fault[K]{Fault: f})
+}
+
+// InjectError makes op fail with err for the given keys (all keys if none).
+func (c *Cache[K, V]) InjectError(op Op, err error, keys ...K) {
+	c.Inject(Fault[K]{Op: op, Keys: keys, Err: err})
+}
+
+// InjectLatency delays op by d for the given keys (all keys if none).
+func (c *Cache[K, V]) InjectLatency(op Op, d time.Duration, keys ...K) {
+	c.Inject(Fault[K]{Op: op, Keys: keys, Latency: d})
+}
+
+// ClearFaults removes all injected faults.
+func (c *Cache[K, V]) ClearFaults() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults = nil
+}
+
+// Clock returns the fake clock driving TTL expiry.
+func (c *Cache[K, V]) Clock() *cache.FakeClock {
+	return c.clock
+}
+
+// Now returns the current virtual clock time.
+func (c *Cache[K, V]) Now() time.Time {
+	return c.clock.Now()
+}
+
+// Advance moves the virtual clock forward by d. Entries whose TTL elapses
+// are reported as misses from then on, without any real sleeping.
+func (c *Cache[K, V]) Advance(d time.Duration) {
+	c.clock.Advance(d)
+}
+
+// Calls returns a copy of the recorded call log, in call order.
+func (c *Cache[K, V]) Calls() []Call[K] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := make([]Call[K], len(c.calls))
+	copy(calls, c.calls)
+
+	return calls
+}
+
+// CallCount returns how many calls of op were recorded (all calls for OpAny).
+func (c *Cache[K, V]) CallCount(op Op) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if op == OpAny {
+		return len(c.calls)
+	}
+
+	count := 0
+	for _, call := range c.calls {
+		if call.Op == op {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ResetCalls clears the call log.
+func (c *Cache[K, V]) ResetCalls() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = nil
+}
+
+// Get retrieves a value by key. Returns cache.ErrMiss if not found or expired
+// on the virtual clock, or the injected error for a matching fault.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	at := c.Now()
+
+	value, err := c.get(ctx, key, OpGet)
+	c.record(OpGet, key, err, at)
+
+	return value, err
+}
+
+// Set stores a value with optional per-key TTL measured on the virtual clock.
+func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	at := c.Now()
+
+	err := c.set(ctx, key, value, OpSet, ttl...)
+	c.record(OpSet, key, err, at)
+
+	return err
+}
+
+// Delete removes a key from the cache.
+func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	at := c.Now()
+
+	err := c.apply(ctx, OpDelete, key, true)
+	if err == nil {
+		err = c.inner.Delete(ctx, key)
+	}
+
+	c.record(OpDelete, key, err, at)
+
+	return err
+}
+
+// GetOrSet returns the existing value or computes, stores, and returns it.
+// It is recorded as a single OpGetOrSet call; faults for OpGet and OpSet
+// do not apply to its internal lookup and store.
+func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, error), ttl ...time.Duration) (V, error) {
+	at := c.Now()
+
+	value, err := c.getOrSet(ctx, key, setter, ttl...)
+	c.record(OpGetOrSet, key, err, at)
+
+	return value, err
+}
+
+// Close closes the inner cache. An injected OpClose error is returned
+// without closing, so a retry can succeed once the fault is exhausted.
+func (c *Cache[K, V]) Close() error {
+	at := c.Now()
+
+	var zero K
+
+	err := c.apply(context.Background(), OpClose, zero, false)
+	if err == nil {
+		err = c.inner.Close()
+	}
+
+	c.record(OpClose, zero, err, at)
+
+	return err
+}
+
+func (c *Cache[K, V]) get(ctx context.Context, key K, op Op) (V, error) {
+	var zero V
+
+	if err := c.apply(ctx, op, key, true); err != nil {
+		return zero, err
+	}
+
+	return c.inner.Get(ctx, key)
+}
+
+func (c *Cache[K, V]) set(ctx context.Context, key K, value V, op Op, ttl ...time.Duration) error {
+	if err := c.apply(ctx, op, key, true); err != nil {
+		return err
+	}
+
+	return c.inner.Set(ctx, key, value, ttl...)
+}
+
+func (c *Cache[K, V]) getOrSet(
+	ctx context.Context,
+	key K,
+	setter func() (V, error),
+	ttl ...time.Duration,
+) (V, error) {
+	var zero V
+
+	if err := c.apply(ctx, OpGetOrSet, key, true); err != nil {
+		return zero, err
+	}
+
+	value, err := c.get(ctx, key, "")
+	if err == nil {
+		return value, nil
+	}
+
+	if !errors.Is(err, cache.ErrMiss) {
+		return zero, err
+	}
+
+	computed, err := setter()
+	if err != nil {
+		return zero, err
+	}
+
+	if err := c.set(ctx, key, computed, "", ttl...); err != nil {
+		return zero, err
+	}
+
+	return computed, nil
+}
+
+// apply runs the faults matching op and key: it sleeps for their combined
+// latency and returns the first injected error. An empty op matches nothing.
+func (c *Cache[K, V]) apply(ctx context.Context, op Op, key K, hasKey bool) error {
+	if op == "" {
+		return nil
+	}
+
+	var (
+		latency time.Duration
+		err     error
+	)
+
+	c.mu.Lock()
+	for _, f := range c.faults {
+		if !f.matches(op, key, hasKey) {
+			continue
+		}
+
+		f.hits++
+		latency += f.Latency
+
+		if err == nil && f.Err != nil {
+			err = fmt.Errorf("cache/fake: %w", f.Err)
+		}
+	}
+	c.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cache/fake: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+func (c *Cache[K, V]) record(op Op, key K, err error, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, Call[K]{Op: op, Key: key, Err: err, At: at})
+}
+
+// compile-time interface assertion
+var _ cache.Cache[string, any] = (*Cache[string, any])(nil)
@@ -0,0 +1,231 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache"
+	"github.com/guionardo/go/cache/fake"
+)
+
+var errBoom = errors.New("boom")
+
+func TestFakeCache_Faults(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	t.Run("error_for_specific_key", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectError(fake.OpGet, errBoom, "bad")
+
+		require.NoError(t, c.Set(t.Context(), "bad", "v"))
+		require.NoError(t, c.Set(t.Context(), "good", "v"))
+
+		_, err := c.Get(t.Context(), "bad")
+		require.ErrorIs(t, err, errBoom)
+
+		got, err := c.Get(t.Context(), "good")
+		require.NoError(t, err)
+		assert.Equal(t, "v", got)
+	})
+
+	t.Run("error_for_any_op", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectError(fake.OpAny, errBoom)
+
+		require.ErrorIs(t, c.Set(t.Context(), "k", "v"), errBoom)
+		require.ErrorIs(t, c.Delete(t.Context(), "k"), errBoom)
+		require.ErrorIs(t, c.Close(), errBoom)
+	})
+
+	t.Run("times_limits_fault", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.Inject(fake.Fault[string]{Op: fake.OpSet, Err: errBoom, Times: 1})
+
+		require.ErrorIs(t, c.Set(t.Context(), "k", "v"), errBoom)
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+	})
+
+	t.Run("clear_faults", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectError(fake.OpSet, errBoom)
+		c.ClearFaults()
+
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+	})
+
+	t.Run("latency_delays_operation", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectLatency(fake.OpSet, 20*time.Millisecond)
+
+		start := time.Now()
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("latency_honors_context", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectLatency(fake.OpGet, time.Hour)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := c.Get(ctx, "k")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("get_or_set_fault", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.InjectError(fake.OpGetOrSet, errBoom, "k")
+
+		_, err := c.GetOrSet(t.Context(), "k", func() (string, error) {
+			t.Fatal("setter must not run when GetOrSet fails")
+
+			return "", nil
+		})
+		require.ErrorIs(t, err, errBoom)
+	})
+
+	t.Run("close_fault_keeps_cache_open", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string]()
+		c.Inject(fake.Fault[string]{Op: fake.OpClose, Err: errBoom, Times: 1})
+
+		require.ErrorIs(t, c.Close(), errBoom)
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+		require.NoError(t, c.Close())
+		require.ErrorIs(t, c.Set(t.Context(), "k", "v"), cache.ErrClosed)
+	})
+}
+
+func TestFakeCache_Clock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("advance_expires_default_ttl", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string](cache.WithDefaultTTL(time.Minute))
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+
+		c.Advance(59 * time.Second)
+		_, err := c.Get(t.Context(), "k")
+		require.NoError(t, err)
+
+		c.Advance(time.Second)
+		_, err = c.Get(t.Context(), "k")
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+
+	t.Run("per_key_ttl_overrides_default", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string](cache.WithDefaultTTL(time.Hour))
+		require.NoError(t, c.Set(t.Context(), "k", "v", time.Second))
+
+		c.Advance(time.Second)
+		_, err := c.Get(t.Context(), "k")
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+
+	t.Run("no_default_ttl_never_expires", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, string](cache.WithDefaultTTL(0))
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+
+		c.Advance(24 * time.Hour)
+		_, err := c.Get(t.Context(), "k")
+		require.NoError(t, err)
+	})
+
+	t.Run("get_or_set_recomputes_after_expiry", func(t *testing.T) {
+		t.Parallel()
+
+		c := fake.New[string, int](cache.WithDefaultTTL(time.Minute))
+		calls := 0
+		setter := func() (int, error) {
+			calls++
+
+			return calls, nil
+		}
+
+		got, err := c.GetOrSet(t.Context(), "k", setter)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+
+		got, err = c.GetOrSet(t.Context(), "k", setter)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+
+		c.Advance(time.Minute)
+		got, err = c.GetOrSet(t.Context(), "k", setter)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got)
+	})
+}
+
+func TestFakeCache_Calls(t *testing.T) {
+	t.Parallel()
+
+	c := fake.New[string, string]()
+	c.InjectError(fake.OpDelete, errBoom)
+	start := c.Now()
+
+	_ = c.Set(t.Context(), "a", "1")
+	c.Advance(time.Second)
+	_, _ = c.Get(t.Context(), "a")
+	_ = c.Delete(t.Context(), "a")
+
+	calls := c.Calls()
+	require.Len(t, calls, 3)
+
+	assert.Equal(t, fake.OpSet, calls[0].Op)
+	assert.Equal(t, "a", calls[0].Key)
+	assert.Equal(t, start, calls[0].At)
+	require.NoError(t, calls[0].Err)
+
+	assert.Equal(t, fake.OpGet, calls[1].Op)
+	assert.Equal(t, start.Add(time.Second), calls[1].At)
+
+	assert.Equal(t, fake.OpDelete, calls[2].Op)
+	require.ErrorIs(t, calls[2].Err, errBoom)
+
+	assert.Equal(t, 1, c.CallCount(fake.OpGet))
+	assert.Equal(t, 3, c.CallCount(fake.OpAny))
+
+	c.ResetCalls()
+	assert.Empty(t, c.Calls())
+}
+
+func TestFakeCache_SharedClock(t *testing.T) {
+	t.Parallel()
+
+	clock := cache.NewFakeClock(time.Now())
+	c := fake.New[string, string](cache.WithClock(clock), cache.WithDefaultTTL(time.Minute))
+	assert.Same(t, clock, c.Clock())
+
+	require.NoError(t, c.Set(t.Context(), "k", "v"))
+	clock.Advance(time.Minute)
+
+	_, err := c.Get(t.Context(), "k")
+	require.ErrorIs(t, err, cache.ErrMiss)
+}
@@ -0,0 +1,64 @@
+package fake
+
+import (
+	"slices"
+	"time"
+)
+
+type (
+	// Op identifies a cache operation for fault matching and the call log.
+	Op string
+
+	// Fault scripts a failure for matching operations.
+	//
+	// A Fault matches when its Op equals the operation (or is OpAny) and,
+	// if Keys is not empty, the key is one of Keys. Matching calls sleep for
+	// Latency (honoring context cancellation) and then fail with Err, if set.
+	// Times limits how many calls the fault applies to; 0 means unlimited.
+	Fault[K comparable] struct {
+		Op      Op
+		Keys    []K
+		Err     error
+		Latency time.Duration
+		Times   int
+	}
+
+	// fault is an injected Fault with its remaining hit budget.
+	fault[K comparable] struct {
+		Fault[K]
+
+		hits int
+	}
+)
+
+const (
+	// OpAny matches every operation in a Fault.
+	OpAny Op = "*"
+	// OpGet is Cache.Get.
+	OpGet Op = "Get"
+	// OpSet is Cache.Set.
+	OpSet Op = "Set"
+	// OpDelete is Cache.Delete.
+	OpDelete Op = "Delete"
+	// OpGetOrSet is Cache.GetOrSet.
+	OpGetOrSet Op = "GetOrSet"
+	// OpClose is Cache.Close.
+	OpClose Op = "Close"
+)
+
+// matches reports whether the fault applies to op on key and still has budget.
+func (f *fault[K]) matches(op Op, key K, hasKey bool) bool {
+	if f.Times > 0 && f.hits >= f.Times {
+		return false
+	}
+
+	if f.Op != OpAny && f.Op != op {
+		return false
+	}
+
+	if len(f.Keys) == 0 {
+		return true
+	}
+
+	return hasKey && slices.Contains(f.Keys, key)
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// FakeClock is a manually driven Clock for tests.
+	// Time only moves on Advance or Set; tickers fire synchronously from
+	// those calls, so expiry and sweep loops can be tested without sleeping.
+	FakeClock struct {
+		mu      sync.Mutex
+		now     time.Time
+		tickers []*fakeTicker
+	}
+
+	fakeTicker struct {
+		clock  *FakeClock
+		period time.Duration
+		next   time.Time
+		ch     chan time.Time
+	}
+)
+
+// NewFakeClock creates a FakeClock starting at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTicker returns a Ticker that fires when the fake time passes each period.
+// Like time.Ticker, ticks are dropped when the receiver falls behind.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("cache: non-positive interval for FakeClock.NewTicker")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{
+		clock:  c,
+		period: d,
+		next:   c.now.Add(d),
+		ch:     make(chan time.Time, 1),
+	}
+	c.tickers = append(c.tickers, t)
+
+	return t
+}
+
+// Advance moves the fake time forward by d and fires due tickers.
+// Concurrent calls are atomic: every advance is applied.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setLocked(c.now.Add(d))
+}
+
+// Set moves the fake time to t and fires due tickers.
+// Moving backwards is allowed and fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setLocked(t)
+}
+
+// setLocked stores t as the fake time and fires due tickers.
+// Caller MUST hold c.mu.
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+
+	for _, ticker := range c.tickers {
+		if t.Before(ticker.next) {
+			continue
+		}
+
+		select {
+		case ticker.ch <- t:
+		default:
+		}
+
+		for !t.Before(ticker.next) {
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+}
+
+// C returns the ticker channel.
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+// Stop unregisters the ticker from its clock.
+func (t *fakeTicker) Stop() {
+	c := t.clock
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, ticker := range c.tickers {
+		if ticker == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+
+			return
+		}
+	}
+}
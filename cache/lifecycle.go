@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// State is the lifecycle state of a cache provider.
+	State uint8
+
+	// Lifecycle is the open → closing → closed state machine shared by all
+	// providers. The zero value is an open lifecycle, ready to use.
+	//
+	// Every provider operation runs between Acquire and Release; Close stops
+	// new operations (they get ErrClosed), waits for the in-flight ones to
+	// drain up to a timeout and then releases the provider resources exactly once.
+	Lifecycle struct {
+		mu       sync.Mutex
+		state    State
+		inflight sync.WaitGroup
+		closed   chan struct{}
+	}
+)
+
+const (
+	// StateOpen accepts new operations.
+	StateOpen State = iota
+	// StateClosing rejects new operations while in-flight ones drain.
+	StateClosing
+	// StateClosed rejects all operations; resources have been released.
+	StateClosed
+)
+
+// DefaultCloseTimeout is how long Close waits for in-flight operations
+// when a provider has no explicit close timeout.
+const DefaultCloseTimeout = 5 * time.Second
+
+// String returns the state name.
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateClosing:
+		return "closing"
+	case StateClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("State(%d)", uint8(s))
+	}
+}
+
+// State returns the current lifecycle state.
+func (l *Lifecycle) State() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.state
+}
+
+// Acquire registers an in-flight operation.
+// Returns ErrClosed if the lifecycle is closing or closed.
+// Each successful Acquire must be paired with a Release.
+func (l *Lifecycle) Acquire() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.state != StateOpen {
+		return ErrClosed
+	}
+
+	l.inflight.Add(1)
+
+	return nil
+}
+
+// Release marks an in-flight operation as finished.
+func (l *Lifecycle) Release() {
+	l.inflight.Done()
+}
+
+// Close transitions to closing, waits up to timeout for in-flight operations
+// and then calls release (which may be nil) to free provider resources.
+// A timeout <= 0 uses DefaultCloseTimeout.
+//
+// Close is idempotent: only the first call drains and releases, concurrent
+// callers wait for it to finish and later calls return nil.
+// If the drain times out, release is still called and the returned error
+// wraps ErrCloseTimeout (joined with any release error).
+func (l *Lifecycle) Close(timeout time.Duration, release func() error) error {
+	l.mu.Lock()
+	if l.state != StateOpen {
+		closed := l.closed
+		l.mu.Unlock()
+		<-closed
+
+		return nil
+	}
+
+	l.state = StateClosing
+	l.closed = make(chan struct{})
+	l.mu.Unlock()
+
+	if timeout <= 0 {
+		timeout = DefaultCloseTimeout
+	}
+
+	var err error
+	if !l.drain(timeout) {
+		err = fmt.Errorf("%w after %s", ErrCloseTimeout, timeout)
+	}
+
+	if release != nil {
+		err = errors.Join(err, release())
+	}
+
+	l.mu.Lock()
+	l.state = StateClosed
+	close(l.closed)
+	l.mu.Unlock()
+
+	return err
+}
+
+// drain waits for in-flight operations, reporting false on timeout.
+func (l *Lifecycle) drain(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		l.inflight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,116 @@
+package cache_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache"
+)
+
+func TestLifecycle(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	t.Run("zero_value_is_open", func(t *testing.T) {
+		t.Parallel()
+
+		var l cache.Lifecycle
+
+		assert.Equal(t, cache.StateOpen, l.State())
+		require.NoError(t, l.Acquire())
+		l.Release()
+	})
+
+	t.Run("acquire_after_close_returns_err_closed", func(t *testing.T) {
+		t.Parallel()
+
+		var l cache.Lifecycle
+		require.NoError(t, l.Close(time.Second, nil))
+
+		assert.Equal(t, cache.StateClosed, l.State())
+		require.ErrorIs(t, l.Acquire(), cache.ErrClosed)
+	})
+
+	t.Run("close_is_idempotent_and_releases_once", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			l     cache.Lifecycle
+			calls atomic.Int32
+		)
+
+		release := func() error {
+			calls.Add(1)
+			return nil
+		}
+
+		require.NoError(t, l.Close(time.Second, release))
+		require.NoError(t, l.Close(time.Second, release))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("close_waits_for_in_flight", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			l        cache.Lifecycle
+			released atomic.Bool
+		)
+
+		require.NoError(t, l.Acquire())
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			released.Store(true)
+			l.Release()
+		}()
+
+		require.NoError(t, l.Close(time.Second, nil))
+		assert.True(t, released.Load())
+	})
+
+	t.Run("close_times_out_and_still_releases", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			l     cache.Lifecycle
+			calls atomic.Int32
+		)
+
+		require.NoError(t, l.Acquire())
+		defer l.Release()
+
+		err := l.Close(10*time.Millisecond, func() error {
+			calls.Add(1)
+			return nil
+		})
+
+		require.ErrorIs(t, err, cache.ErrCloseTimeout)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, cache.StateClosed, l.State())
+	})
+
+	t.Run("close_returns_release_error", func(t *testing.T) {
+		t.Parallel()
+
+		var l cache.Lifecycle
+		releaseErr := errors.New("boom")
+
+		err := l.Close(time.Second, func() error { return releaseErr })
+
+		require.ErrorIs(t, err, releaseErr)
+	})
+}
+
+func TestState_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "open", cache.StateOpen.String())
+	assert.Equal(t, "closing", cache.StateClosing.String())
+	assert.Equal(t, "closed", cache.StateClosed.String())
+	assert.Equal(t, "State(9)", cache.State(9).String())
+}
@@ -2,7 +2,8 @@
 //
 // Thread-safe via sync.RWMutex. A background goroutine sweeps expired
 // entries on a configurable interval. Passive TTL checking also occurs
-// on every Get call for prompt invalidation.
+// on every Get call for prompt invalidation. Both use the injected
+// cache.Clock (cache.WithClock), so a cache.FakeClock makes them deterministic.
 //
 // Zero external dependencies.
 //
@@ -11,3 +11,9 @@ type (
 		expiresAt *time.Time // nil means no expiry
 	}
 )
+
+// expired reports whether the entry has a TTL that has elapsed at now.
+// An entry expires exactly when its TTL is reached.
+func (e *entry[V]) expired(now time.Time) bool {
+	return e.expiresAt != nil && !now.Before(*e.expiresAt)
+}
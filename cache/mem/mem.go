@@ -2,6 +2,7 @@ package mem
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -11,10 +12,13 @@ import (
 
 // Cache is an in-memory cache provider implementing cache.Cache[K, V].
 type Cache[K comparable, V any] struct {
-	mu         sync.RWMutex
-	entries    map[K]*entry[V]
-	defaultTTL time.Duration
-	stop       chan struct{}
+	mu           sync.RWMutex
+	entries      map[K]*entry[V]
+	defaultTTL   time.Duration
+	closeTimeout time.Duration
+	clock        cache.Clock
+	stop         chan struct{}
+	lifecycle    cache.Lifecycle
 }
 
 // New creates a new in-memory cache provider with optional functional options.
@@ -27,19 +31,32 @@ func New[K comparable, V any](opts ...cache.Option) *Cache[K, V] {
 		opt.Apply(&cfg)
 	}
 
+	if cfg.Clock == nil {
+		cfg.Clock = cache.SystemClock()
+	}
+
 	c := &Cache[K, V]{
-		entries:    make(map[K]*entry[V]),
-		stop:       make(chan struct{}),
-		defaultTTL: cfg.DefaultTTL,
+		entries:      make(map[K]*entry[V]),
+		stop:         make(chan struct{}),
+		defaultTTL:   cfg.DefaultTTL,
+		closeTimeout: cfg.CloseTimeout,
+		clock:        cfg.Clock,
 	}
 
-	go c.sweepLoop()
+	go c.sweepLoop(c.clock.NewTicker(sweepInterval))
 
 	return c
 }
 
-// Get retrieves a value by key. Returns cache.ErrMiss if not found or expired.
+// Get retrieves a value by key. Returns cache.ErrMiss if not found or expired
+// and cache.ErrClosed after Close.
 func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if err := c.lifecycle.Acquire(); err != nil {
+		var zero V
+		return zero, fmt.Errorf("cache/mem: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	c.mu.RLock()
 	e, ok := c.entries[key]
 	c.mu.RUnlock()
@@ -50,7 +67,7 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 	}
 
 	// Passive TTL check — backstop for sweep interval
-	if e.expiresAt != nil && time.Now().After(*e.expiresAt) {
+	if e.expired(c.now()) {
 		c.mu.Lock()
 		delete(c.entries, key)
 		c.mu.Unlock()
@@ -63,6 +80,11 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 
 // Set stores a value with optional per-key TTL.
 func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/mem: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	expiresAt := c.resolveTTL(ttl...)
 
 	c.mu.Lock()
@@ -74,6 +96,11 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Durat
 
 // Delete removes a key from the cache.
 func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/mem: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	c.mu.Lock()
 	delete(c.entries, key)
 	c.mu.Unlock()
@@ -88,6 +115,11 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 		return value, nil
 	}
 
+	if errors.Is(err, cache.ErrClosed) {
+		var zero V
+		return zero, err
+	}
+
 	computed, err := setter()
 	if err != nil {
 		var zero V
@@ -102,29 +134,44 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 	return computed, nil
 }
 
-// Close shuts down the background sweep goroutine.
+// Close rejects new operations, waits for in-flight ones and shuts down
+// the background sweep goroutine.
 // Safe to call multiple times (idempotent).
 func (c *Cache[K, V]) Close() error {
-	select {
-	case <-c.stop:
-		// already closed
-	default:
-		close(c.stop)
+	err := c.lifecycle.Close(c.closeTimeout, func() error {
+		if c.stop != nil {
+			close(c.stop)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache/mem: %w", err)
 	}
+
 	return nil
 }
 
 func (c *Cache[K, V]) resolveTTL(ttl ...time.Duration) *time.Time {
 	if len(ttl) > 0 && ttl[0] > 0 {
-		t := time.Now().Add(ttl[0])
+		t := c.now().Add(ttl[0])
 		return &t
 	}
 	if c.defaultTTL > 0 {
-		t := time.Now().Add(c.defaultTTL)
+		t := c.now().Add(c.defaultTTL)
 		return &t
 	}
 	return nil
 }
 
+// now returns the current time from the injected clock (system clock if unset).
+func (c *Cache[K, V]) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+
+	return c.clock.Now()
+}
+
 // compile-time interface assertion
 var _ cache.Cache[string, any] = (*Cache[string, any])(nil)
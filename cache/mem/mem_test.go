@@ -40,24 +40,30 @@ func TestMemCache_SetGet(t *testing.T) { //nolint:funlen
 	t.Run("get_expired_returns_error", func(t *testing.T) {
 		t.Parallel()
 
-		c := mem.New[string, string](cache.WithDefaultTTL(1 * time.Millisecond))
+		clock := cache.NewFakeClock(time.Now())
+		c := mem.New[string, string](cache.WithDefaultTTL(time.Minute), cache.WithClock(clock))
 		_ = c.Set(t.Context(), "k", "v")
-		time.Sleep(10 * time.Millisecond)
 
+		clock.Advance(59 * time.Second)
 		_, err := c.Get(t.Context(), "k")
-		require.Error(t, err)
+		require.NoError(t, err)
+
+		clock.Advance(time.Second)
+		_, err = c.Get(t.Context(), "k")
+		require.ErrorIs(t, err, cache.ErrMiss)
 	})
 
 	t.Run("per_key_ttl_overrides_default", func(t *testing.T) {
 		t.Parallel()
 
-		c := mem.New[string, string](cache.WithDefaultTTL(1 * time.Hour))
-		err := c.Set(t.Context(), "k", "v", 1*time.Millisecond)
+		clock := cache.NewFakeClock(time.Now())
+		c := mem.New[string, string](cache.WithDefaultTTL(1*time.Hour), cache.WithClock(clock))
+		err := c.Set(t.Context(), "k", "v", 1*time.Second)
 		require.NoError(t, err)
-		time.Sleep(10 * time.Millisecond)
 
+		clock.Advance(time.Second)
 		_, err = c.Get(t.Context(), "k")
-		require.Error(t, err)
+		require.ErrorIs(t, err, cache.ErrMiss)
 	})
 
 	t.Run("set_without_ttl_no_expiry", func(t *testing.T) {
@@ -161,6 +167,28 @@ func TestMemCache_Close(t *testing.T) {
 		err := c.Close()
 		require.NoError(t, err)
 	})
+
+	t.Run("operations_after_close_return_err_closed", func(t *testing.T) {
+		t.Parallel()
+
+		c := mem.New[string, string]()
+		require.NoError(t, c.Set(t.Context(), "k", "v"))
+		require.NoError(t, c.Close())
+
+		_, err := c.Get(t.Context(), "k")
+		require.ErrorIs(t, err, cache.ErrClosed)
+		require.ErrorIs(t, c.Set(t.Context(), "k", "v"), cache.ErrClosed)
+		require.ErrorIs(t, c.Delete(t.Context(), "k"), cache.ErrClosed)
+
+		called := false
+		_, err = c.GetOrSet(t.Context(), "k", func() (string, error) {
+			called = true
+
+			return "computed", nil
+		})
+		require.ErrorIs(t, err, cache.ErrClosed)
+		assert.False(t, called, "setter must not run on a closed cache")
+	})
 }
 
 func TestMemCache_Concurrent(t *testing.T) {
@@ -2,16 +2,22 @@ package mem
 
 import (
 	"time"
+
+	"github.com/guionardo/go/cache"
 )
 
-// sweepLoop runs periodically to evict expired entries.
-func (c *Cache[K, V]) sweepLoop() {
-	ticker := time.NewTicker(1 * time.Minute)
+// sweepInterval is how often the background goroutine evicts expired entries.
+const sweepInterval = 1 * time.Minute
+
+// sweepLoop evicts expired entries on every tick until the cache is closed.
+// The ticker is created by the caller so a FakeClock registers it before
+// New returns.
+func (c *Cache[K, V]) sweepLoop(ticker cache.Ticker) {
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			c.sweep()
 		case <-c.stop:
 			return
@@ -24,9 +30,9 @@ func (c *Cache[K, V]) sweep() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	now := time.Now()
+	now := c.now()
 	for k, e := range c.entries {
-		if e.expiresAt != nil && now.After(*e.expiresAt) {
+		if e.expired(now) {
 			delete(c.entries, k)
 		}
 	}
@@ -3,6 +3,10 @@ package mem
 import (
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/guionardo/go/cache"
 )
 
 func TestSweeper_sweep_removes_expired(t *testing.T) {
@@ -56,3 +60,21 @@ func TestSweeper_sweep_all_fresh(t *testing.T) {
 func ptr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestSweeper_sweepLoop_runs_on_clock_tick(t *testing.T) {
+	t.Parallel()
+
+	clock := cache.NewFakeClock(time.Now())
+	c := New[string, string](cache.WithDefaultTTL(30*time.Second), cache.WithClock(clock))
+	t.Cleanup(func() { _ = c.Close() })
+
+	_ = c.Set(t.Context(), "k", "v")
+	clock.Advance(sweepInterval)
+
+	assert.Eventually(t, func() bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		return len(c.entries) == 0
+	}, time.Second, time.Millisecond)
+}
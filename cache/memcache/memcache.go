@@ -3,6 +3,7 @@ package memcache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -13,8 +14,10 @@ import (
 
 // Cache implements cache.Cache[K, V] using a Memcache backend.
 type Cache[K comparable, V any] struct {
-	client     *memcache.Client
-	defaultTTL time.Duration
+	client       *memcache.Client
+	defaultTTL   time.Duration
+	closeTimeout time.Duration
+	lifecycle    cache.Lifecycle
 }
 
 // memcacheResult carries the result of a gomemcache operation for context cancellation.
@@ -35,13 +38,21 @@ func New[K comparable, V any](opts ...Option) *Cache[K, V] {
 	mc.MaxIdleConns = cfg.MaxIdleConns
 
 	return &Cache[K, V]{
-		client:     mc,
-		defaultTTL: cfg.DefaultTTL,
+		client:       mc,
+		defaultTTL:   cfg.DefaultTTL,
+		closeTimeout: cfg.CloseTimeout,
 	}
 }
 
-// Get retrieves a value by key. Returns cache.ErrMiss if not found.
+// Get retrieves a value by key. Returns cache.ErrMiss if not found
+// and cache.ErrClosed after Close.
 func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if err := c.lifecycle.Acquire(); err != nil {
+		var zero V
+		return zero, fmt.Errorf("cache/memcache: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	keyStr := fmt.Sprint(key)
 	ch := make(chan memcacheResult, 1)
 
@@ -76,6 +87,11 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 
 // Set stores a value with optional per-key TTL.
 func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/memcache: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("cache/memcache: %w", err)
@@ -106,6 +122,11 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Durat
 
 // Delete removes a key from the cache. Idempotent — deleting a missing key is not an error.
 func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/memcache: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	ch := make(chan error, 1)
 	go func() {
 		ch <- c.client.Delete(fmt.Sprint(key))
@@ -132,6 +153,11 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 		return value, nil
 	}
 
+	if errors.Is(err, cache.ErrClosed) {
+		var zero V
+		return zero, err
+	}
+
 	computed, err := setter()
 	if err != nil {
 		var zero V
@@ -146,8 +172,20 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 	return computed, nil
 }
 
-// Close is a no-op for memcache — the client does not support Close.
+// Close rejects new operations, waits for in-flight ones and closes the
+// idle memcache connections. Safe to call multiple times (idempotent).
 func (c *Cache[K, V]) Close() error {
+	err := c.lifecycle.Close(c.closeTimeout, func() error {
+		if c.client != nil {
+			return c.client.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache/memcache: %w", err)
+	}
+
 	return nil
 }
 
@@ -1,10 +1,17 @@
 package memcache
 
 import (
+	"bufio"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache"
+	"github.com/guionardo/go/cache/cachetest"
 )
 
 func TestResolveTTL(t *testing.T) {
@@ -64,3 +71,52 @@ func TestResolveTTL(t *testing.T) {
 		assert.Equal(t, int32(30), got)
 	})
 }
+
+func TestClosedCache(t *testing.T) {
+	t.Parallel()
+
+	addr, idleClosed := idleConnServer(t)
+
+	c := New[string, string](WithServers(addr))
+	_, err := c.Get(t.Context(), "k")
+	require.ErrorIs(t, err, cache.ErrMiss)
+
+	cachetest.Closed(t, c)
+
+	select {
+	case <-idleClosed:
+	case <-time.After(time.Second):
+		t.Fatal("the idle client connection must be closed")
+	}
+}
+
+// idleConnServer serves one memcache connection that answers every get as a
+// miss; the returned channel is closed when the client closes it.
+func idleConnServer(t *testing.T) (string, <-chan struct{}) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	closed := make(chan struct{})
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "get") {
+				_, _ = conn.Write([]byte("END\r\n"))
+			}
+		}
+
+		close(closed)
+	}()
+
+	return listener.Addr().String(), closed
+}
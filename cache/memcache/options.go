@@ -7,6 +7,7 @@ type Config struct {
 	Servers      []string
 	Timeout      time.Duration
 	DefaultTTL   time.Duration
+	CloseTimeout time.Duration
 	MaxIdleConns int
 }
 
@@ -48,3 +49,10 @@ func WithMaxIdleConns(n int) Option {
 		cfg.MaxIdleConns = n
 	}
 }
+
+// WithCloseTimeout sets how long Close waits for in-flight operations to drain.
+func WithCloseTimeout(timeout time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.CloseTimeout = timeout
+	}
+}
@@ -6,7 +6,10 @@ import (
 
 // Config holds shared cache configuration for providers.
 type Config struct {
-	DefaultTTL time.Duration
+	DefaultTTL   time.Duration
+	CloseTimeout time.Duration
+	// Clock is the time source for TTLs and sweeps. Nil means SystemClock.
+	Clock Clock
 }
 
 // Option is a functional option for configuring a cache provider.
@@ -28,3 +31,17 @@ func WithDefaultTTL(ttl time.Duration) Option {
 		cfg.DefaultTTL = ttl
 	})
 }
+
+// WithCloseTimeout sets how long Close waits for in-flight operations to drain.
+func WithCloseTimeout(timeout time.Duration) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.CloseTimeout = timeout
+	})
+}
+
+// WithClock sets the time source used for TTL expiry and background sweeps.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Clock = clock
+	})
+}
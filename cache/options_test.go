@@ -38,3 +38,23 @@ func TestWithDefaultTTL_Zero(t *testing.T) {
 
 	assert.Equal(t, time.Duration(0), cfg.DefaultTTL)
 }
+
+func TestWithCloseTimeout(t *testing.T) {
+	t.Parallel()
+
+	opt := WithCloseTimeout(2 * time.Second)
+	cfg := &Config{}
+	opt.Apply(cfg)
+
+	assert.Equal(t, 2*time.Second, cfg.CloseTimeout)
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Now())
+	cfg := &Config{}
+	WithClock(clock).Apply(cfg)
+
+	assert.Same(t, clock, cfg.Clock)
+}
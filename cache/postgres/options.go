@@ -1,6 +1,10 @@
 package postgres
 
-import "time"
+import (
+	"time"
+
+	"github.com/guionardo/go/cache"
+)
 
 // Config holds configuration for the Postgres cache provider.
 type Config struct {
@@ -9,6 +13,8 @@ type Config struct {
 	PoolSize      int
 	SweepInterval time.Duration
 	DefaultTTL    time.Duration
+	CloseTimeout  time.Duration
+	Clock         cache.Clock
 }
 
 // Option is a functional option for configuring the Postgres cache provider.
@@ -19,6 +25,7 @@ func defaultConfig() *Config {
 		TableName:     "cache_entries",
 		PoolSize:      5,
 		SweepInterval: 1 * time.Minute,
+		Clock:         cache.SystemClock(),
 	}
 }
 
@@ -58,3 +65,22 @@ func WithDefaultTTL(ttl time.Duration) Option {
 		cfg.DefaultTTL = ttl
 	}
 }
+
+// WithCloseTimeout sets how long Close waits for in-flight operations to drain.
+func WithCloseTimeout(timeout time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.CloseTimeout = timeout
+	}
+}
+
+// WithClock sets the time source used for TTL expiry and the sweep loop.
+// Defaults to the system clock; nil is ignored. With the system clock, Get
+// and the sweep check expiry against the database NOW(); another clock
+// (e.g. cache.FakeClock) is checked against its own time.
+func WithClock(clock cache.Clock) Option {
+	return func(cfg *Config) {
+		if clock != nil {
+			cfg.Clock = clock
+		}
+	}
+}
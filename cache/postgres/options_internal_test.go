@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/guionardo/go/cache"
 )
 
 func TestOptions_WithConnString(t *testing.T) {
@@ -63,3 +65,17 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Empty(t, cfg.ConnString)
 	assert.Equal(t, time.Duration(0), cfg.DefaultTTL)
 }
+
+func TestOptions_WithClock(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultConfig()
+	assert.NotNil(t, cfg.Clock)
+
+	clock := cache.NewFakeClock(time.Now())
+	WithClock(clock)(cfg)
+	assert.Same(t, clock, cfg.Clock)
+
+	WithClock(nil)(cfg)
+	assert.Same(t, clock, cfg.Clock, "nil clock must be ignored")
+}
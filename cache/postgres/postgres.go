@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -24,7 +25,10 @@ type Cache[K comparable, V any] struct {
 	tableName     string
 	defaultTTL    time.Duration
 	sweepInterval time.Duration
+	closeTimeout  time.Duration
+	clock         cache.Clock
 	stop          chan struct{}
+	lifecycle     cache.Lifecycle
 }
 
 // New creates a new Postgres cache provider with optional functional options.
@@ -54,23 +58,32 @@ func New[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
 		tableName:     cfg.TableName,
 		defaultTTL:    cfg.DefaultTTL,
 		sweepInterval: cfg.SweepInterval,
+		closeTimeout:  cfg.CloseTimeout,
+		clock:         cfg.Clock,
 		stop:          make(chan struct{}),
 	}
 
-	go c.sweepLoop()
+	go c.sweepLoop(c.clock.NewTicker(c.sweepInterval))
 
 	return c, nil
 }
 
-// Get retrieves a value by key. Returns cache.ErrMiss if not found or expired.
+// Get retrieves a value by key. Returns cache.ErrMiss if not found or expired
+// and cache.ErrClosed after Close.
 func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if err := c.lifecycle.Acquire(); err != nil {
+		var zero V
+		return zero, fmt.Errorf("cache/postgres: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	query := fmt.Sprintf(
-		"SELECT value FROM %s WHERE cache_key = $1 AND (expires_at IS NULL OR expires_at > NOW())",
+		"SELECT value FROM %s WHERE cache_key = $1 AND (expires_at IS NULL OR expires_at > COALESCE($2::timestamptz, NOW()))",
 		pgx.Identifier{c.tableName}.Sanitize(),
 	)
 
 	var valueJSON string
-	err := c.pool.QueryRow(ctx, query, fmt.Sprint(key)).Scan(&valueJSON)
+	err := c.pool.QueryRow(ctx, query, fmt.Sprint(key), c.expiryNow()).Scan(&valueJSON)
 	if err == pgx.ErrNoRows {
 		var zero V
 		return zero, fmt.Errorf("cache/postgres: %w", cache.ErrMiss)
@@ -91,6 +104,11 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 
 // Set stores a value with optional per-key TTL.
 func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/postgres: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("cache/postgres: %w", err)
@@ -112,6 +130,11 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Durat
 
 // Delete removes a key from the cache. Idempotent — deleting a missing key succeeds.
 func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/postgres: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	query := fmt.Sprintf(
 		"DELETE FROM %s WHERE cache_key = $1",
 		pgx.Identifier{c.tableName}.Sanitize(),
@@ -131,6 +154,11 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 		return value, nil
 	}
 
+	if errors.Is(err, cache.ErrClosed) {
+		var zero V
+		return zero, err
+	}
+
 	computed, err := setter()
 	if err != nil {
 		var zero V
@@ -145,17 +173,25 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 	return computed, nil
 }
 
-// Close shuts down the background sweep goroutine and closes the connection pool.
-// Safe to call multiple times (idempotent).
+// Close rejects new operations, waits for in-flight ones (including a running
+// sweep), stops the background sweep goroutine and closes the connection pool.
+// Safe to call multiple times (idempotent) — the pool is closed exactly once.
 func (c *Cache[K, V]) Close() error {
-	select {
-	case <-c.stop:
-		// already closed
-	default:
-		close(c.stop)
+	err := c.lifecycle.Close(c.closeTimeout, func() error {
+		if c.stop != nil {
+			close(c.stop)
+		}
+
+		if c.pool != nil {
+			c.pool.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache/postgres: %w", err)
 	}
 
-	c.pool.Close()
 	return nil
 }
 
@@ -163,15 +199,38 @@ func (c *Cache[K, V]) Close() error {
 // Returns nil for no expiry.
 func (c *Cache[K, V]) resolveTTL(ttl ...time.Duration) *time.Time {
 	if len(ttl) > 0 && ttl[0] > 0 {
-		t := time.Now().Add(ttl[0])
+		t := c.now().Add(ttl[0])
 		return &t
 	}
 	if c.defaultTTL > 0 {
-		t := time.Now().Add(c.defaultTTL)
+		t := c.now().Add(c.defaultTTL)
 		return &t
 	}
 	return nil
 }
 
+// now returns the current time from the injected clock (system clock if unset).
+func (c *Cache[K, V]) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+
+	return c.clock.Now()
+}
+
+// expiryNow returns the time Get and sweep check expiry against: nil, for
+// the database NOW(), with the system clock, so that every instance agrees
+// whatever its own clock; the injected clock time otherwise (e.g. a
+// cache.FakeClock in tests).
+func (c *Cache[K, V]) expiryNow() *time.Time {
+	if c.clock == nil || c.clock == cache.SystemClock() {
+		return nil
+	}
+
+	t := c.clock.Now()
+
+	return &t
+}
+
 // compile-time interface assertion
 var _ cache.Cache[string, any] = (*Cache[string, any])(nil)
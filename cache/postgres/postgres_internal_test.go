@@ -4,7 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache"
+	"github.com/guionardo/go/cache/cachetest"
 )
 
 func TestResolveTTL(t *testing.T) {
@@ -49,3 +54,54 @@ func TestResolveTTL(t *testing.T) {
 		assert.Nil(t, got)
 	})
 }
+
+func TestClosedCache(t *testing.T) {
+	t.Parallel()
+
+	// pgxpool connects lazily, so the pool can be built without a server.
+	pool, err := pgxpool.New(t.Context(), "postgres://127.0.0.1:1/cache")
+	require.NoError(t, err)
+
+	c := &Cache[string, string]{pool: pool, stop: make(chan struct{})}
+	cachetest.Closed(t, c)
+
+	select {
+	case <-c.stop:
+	default:
+		t.Fatal("the sweep goroutine must be stopped")
+	}
+
+	_, err = pool.Acquire(t.Context())
+	require.Error(t, err, "the pool must be closed")
+}
+
+func TestResolveTTL_UsesClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := cache.NewFakeClock(start)
+	c := &Cache[string, string]{defaultTTL: time.Minute, clock: clock}
+
+	got := c.resolveTTL()
+	require.NotNil(t, got)
+	assert.Equal(t, start.Add(time.Minute), *got)
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), c.now())
+}
+
+func TestExpiryNow(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, (&Cache[string, string]{}).expiryNow(), "no clock: database NOW()")
+	assert.Nil(t, (&Cache[string, string]{clock: cache.SystemClock()}).expiryNow(), "system clock: database NOW()")
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := cache.NewFakeClock(start)
+	c := &Cache[string, string]{clock: clock}
+
+	clock.Advance(time.Minute)
+	got := c.expiryNow()
+	require.NotNil(t, got)
+	assert.Equal(t, start.Add(time.Minute), *got)
+}
@@ -4,19 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/jackc/pgx/v5"
+
+	"github.com/guionardo/go/cache"
 )
 
-// sweepLoop runs periodically to delete expired cache entries.
-func (c *Cache[K, V]) sweepLoop() {
-	ticker := time.NewTicker(c.sweepInterval)
+// sweepLoop runs on every tick to delete expired cache entries.
+func (c *Cache[K, V]) sweepLoop(ticker cache.Ticker) {
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			c.sweep()
 		case <-c.stop:
 			return
@@ -25,14 +25,20 @@ func (c *Cache[K, V]) sweepLoop() {
 }
 
 // sweep deletes all expired entries from the cache table.
-// Sweep is best-effort maintenance — errors are logged but not returned.
+// Sweep is best-effort maintenance — errors are logged but not returned,
+// and a sweep racing with Close is skipped.
 func (c *Cache[K, V]) sweep() {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return
+	}
+	defer c.lifecycle.Release()
+
 	query := fmt.Sprintf(
-		"DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at < NOW()",
+		"DELETE FROM %s WHERE expires_at IS NOT NULL AND expires_at <= COALESCE($1::timestamptz, NOW())",
 		pgx.Identifier{c.tableName}.Sanitize(),
 	)
 
-	if _, err := c.pool.Exec(context.Background(), query); err != nil {
+	if _, err := c.pool.Exec(context.Background(), query, c.expiryNow()); err != nil {
 		slog.Warn("cache/postgres: sweep failed", "error", err)
 	}
 }
@@ -4,11 +4,12 @@ import "time"
 
 // Config holds Redis-specific cache configuration.
 type Config struct {
-	Addr       string
-	Password   string
-	DB         int
-	PoolSize   int
-	DefaultTTL time.Duration
+	Addr         string
+	Password     string
+	DB           int
+	PoolSize     int
+	DefaultTTL   time.Duration
+	CloseTimeout time.Duration
 }
 
 // Option is a functional option for configuring a Redis cache provider.
@@ -55,3 +56,10 @@ func WithDefaultTTL(ttl time.Duration) Option {
 		cfg.DefaultTTL = ttl
 	}
 }
+
+// WithCloseTimeout sets how long Close waits for in-flight operations to drain.
+func WithCloseTimeout(timeout time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.CloseTimeout = timeout
+	}
+}
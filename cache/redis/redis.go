@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,8 +14,10 @@ import (
 
 // Cache is a Redis-backed generic cache implementation.
 type Cache[K comparable, V any] struct {
-	client     *redis.Client
-	defaultTTL time.Duration
+	client       *redis.Client
+	defaultTTL   time.Duration
+	closeTimeout time.Duration
+	lifecycle    cache.Lifecycle
 }
 
 // New creates a new Redis cache provider.
@@ -32,15 +35,22 @@ func New[K comparable, V any](opts ...Option) *Cache[K, V] {
 	})
 
 	return &Cache[K, V]{
-		client:     client,
-		defaultTTL: cfg.DefaultTTL,
+		client:       client,
+		defaultTTL:   cfg.DefaultTTL,
+		closeTimeout: cfg.CloseTimeout,
 	}
 }
 
-// Get retrieves a value by key. Returns cache.ErrMiss if not found.
+// Get retrieves a value by key. Returns cache.ErrMiss if not found
+// and cache.ErrClosed after Close.
 func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 	var zero V
 
+	if err := c.lifecycle.Acquire(); err != nil {
+		return zero, fmt.Errorf("cache/redis: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	data, err := c.client.Get(ctx, fmt.Sprint(key)).Bytes()
 	if err == redis.Nil {
 		return zero, fmt.Errorf("cache/redis: %w", cache.ErrMiss)
@@ -60,6 +70,11 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 // Set stores a value with optional per-key TTL.
 // If ttl is empty, the provider-level default TTL is used.
 func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/redis: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("cache/redis: %w", err)
@@ -75,6 +90,11 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Durat
 
 // Delete removes a key from the cache.
 func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/redis: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	if err := c.client.Del(ctx, fmt.Sprint(key)).Err(); err != nil {
 		return fmt.Errorf("cache/redis: %w", err)
 	}
@@ -91,6 +111,10 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 		return value, nil
 	}
 
+	if errors.Is(err, cache.ErrClosed) {
+		return zero, err
+	}
+
 	value, err = setter()
 	if err != nil {
 		return zero, fmt.Errorf("cache/redis: %w", err)
@@ -103,9 +127,21 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 	return value, nil
 }
 
-// Close cleans up the Redis connection.
+// Close rejects new operations, waits for in-flight ones and closes the
+// Redis connection pool. Safe to call multiple times (idempotent).
 func (c *Cache[K, V]) Close() error {
-	return c.client.Close()
+	err := c.lifecycle.Close(c.closeTimeout, func() error {
+		if c.client != nil {
+			return c.client.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache/redis: %w", err)
+	}
+
+	return nil
 }
 
 // resolveTTL resolves the effective TTL for a Set operation.
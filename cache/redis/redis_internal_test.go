@@ -4,7 +4,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache/cachetest"
 )
 
 func TestResolveTTL(t *testing.T) {
@@ -46,3 +50,12 @@ func TestResolveTTL(t *testing.T) {
 		assert.Equal(t, time.Duration(0), got)
 	})
 }
+
+func TestClosedCache(t *testing.T) {
+	t.Parallel()
+
+	c := New[string, string](WithAddr("127.0.0.1:1"))
+	cachetest.Closed(t, c)
+
+	require.ErrorIs(t, c.client.Ping(t.Context()).Err(), redis.ErrClosed, "the client must be closed")
+}
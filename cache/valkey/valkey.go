@@ -14,9 +14,11 @@ import (
 
 // Cache is a Valkey-backed generic cache implementation.
 type Cache[K comparable, V any] struct {
-	client     valkey.Client
-	initErr    error
-	defaultTTL time.Duration
+	client       valkey.Client
+	initErr      error
+	defaultTTL   time.Duration
+	closeTimeout time.Duration
+	lifecycle    cache.Lifecycle
 }
 
 // New creates a new Valkey cache provider.
@@ -34,16 +36,23 @@ func New[K comparable, V any](opts ...Option) *Cache[K, V] {
 	})
 
 	return &Cache[K, V]{
-		client:     client,
-		initErr:    err,
-		defaultTTL: cfg.DefaultTTL,
+		client:       client,
+		initErr:      err,
+		defaultTTL:   cfg.DefaultTTL,
+		closeTimeout: cfg.CloseTimeout,
 	}
 }
 
-// Get retrieves a value by key. Returns cache.ErrMiss if not found.
+// Get retrieves a value by key. Returns cache.ErrMiss if not found
+// and cache.ErrClosed after Close.
 func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 	var zero V
 
+	if err := c.lifecycle.Acquire(); err != nil {
+		return zero, fmt.Errorf("cache/valkey: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	if c.initErr != nil {
 		return zero, fmt.Errorf("cache/valkey: %w", c.initErr)
 	}
@@ -67,6 +76,11 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
 // Set stores a value with optional per-key TTL.
 // If ttl is empty, the provider-level default TTL is used.
 func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Duration) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/valkey: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	if c.initErr != nil {
 		return fmt.Errorf("cache/valkey: %w", c.initErr)
 	}
@@ -90,6 +104,11 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value V, ttl ...time.Durat
 
 // Delete removes a key from the cache.
 func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := c.lifecycle.Acquire(); err != nil {
+		return fmt.Errorf("cache/valkey: %w", err)
+	}
+	defer c.lifecycle.Release()
+
 	if c.initErr != nil {
 		return fmt.Errorf("cache/valkey: %w", c.initErr)
 	}
@@ -111,6 +130,10 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 		return value, nil
 	}
 
+	if errors.Is(err, cache.ErrClosed) {
+		return zero, err
+	}
+
 	// Only call setter on miss errors, not connection errors
 	if c.initErr != nil {
 		return zero, fmt.Errorf("cache/valkey: %w", c.initErr)
@@ -128,11 +151,20 @@ func (c *Cache[K, V]) GetOrSet(ctx context.Context, key K, setter func() (V, err
 	return value, nil
 }
 
-// Close cleans up the Valkey connection.
+// Close rejects new operations, waits for in-flight ones and closes the
+// Valkey connection. Safe to call multiple times (idempotent).
 func (c *Cache[K, V]) Close() error {
-	if c.client != nil {
-		c.client.Close()
+	err := c.lifecycle.Close(c.closeTimeout, func() error {
+		if c.client != nil {
+			c.client.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache/valkey: %w", err)
 	}
+
 	return nil
 }
 
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/cache/cachetest"
 )
 
 func TestResolveTTL(t *testing.T) {
@@ -46,3 +49,17 @@ func TestResolveTTL(t *testing.T) {
 		assert.Equal(t, time.Duration(0), got)
 	})
 }
+
+func TestClosedCache(t *testing.T) {
+	t.Parallel()
+
+	// valkey connects eagerly: without a server the client is nil and the
+	// connection error is kept, but a closed cache still reports ErrClosed.
+	c := New[string, string](WithAddr("127.0.0.1:1"))
+	require.Error(t, c.initErr)
+
+	cachetest.Closed(t, c)
+
+	_, err := c.Get(t.Context(), "k")
+	require.NotErrorIs(t, err, c.initErr)
+}
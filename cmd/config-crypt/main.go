@@ -0,0 +1,313 @@
+// Package main provides a CLI to encrypt, decrypt and edit encrypted
+// configuration profiles (see config/crypt).
+//
+// Keys are read like config.Provider does, from CONFIG_KEY / CONFIG_KEY_FILE
+// (AES-GCM) and CONFIG_AGE_IDENTITY / CONFIG_AGE_IDENTITY_FILE (age):
+//
+//	config-crypt keygen                       # new AES key, for CONFIG_KEY
+//	config-crypt keygen -age                  # new age identity (public key in a comment)
+//	config-crypt encrypt production.yaml      # writes production.yaml.enc with CONFIG_KEY
+//	config-crypt encrypt -recipient age1... production.yaml
+//	config-crypt decrypt production.yaml.enc  # prints the profile
+//	config-crypt edit production.yaml.enc     # decrypts, runs $EDITOR, encrypts again
+//	config-crypt edit -recipient age1... -recipient age1... production.yaml.enc
+//
+// age files do not record their recipients, so editing one needs all of
+// them as -recipient flags; the edit fails before running the editor
+// otherwise, instead of re-encrypting for the local identities only.
+//
+// It exits 0 on success, 1 on errors and 2 on usage errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guionardo/go/config/crypt"
+	shelltools "github.com/guionardo/go/shell_tools"
+)
+
+type (
+	// stringList is a repeatable string flag.
+	stringList []string
+)
+
+var (
+	errUsage = errors.New("usage: config-crypt keygen|encrypt|decrypt|edit [flags] [file]")
+
+	errAgeRecipients = errors.New("age profiles do not record their recipients: pass every one with -recipient")
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, errUsage)
+		return 2
+	}
+
+	commands := map[string]func([]string, io.Writer) error{
+		"keygen":  keygen,
+		"encrypt": encrypt,
+		"decrypt": decrypt,
+		"edit":    edit,
+	}
+
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, errUsage)
+		return 2
+	}
+
+	if err := command(args[1:], stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+func keygen(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	ageKey := flags.Bool("age", false, "generate an age identity instead of an AES key")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *ageKey {
+		identity, err := crypt.GenerateAgeIdentity()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(stdout, "# public key: %s\n%s\n", identity.Recipient(), identity)
+
+		return err
+	}
+
+	key, err := crypt.GenerateAESKey()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, key)
+
+	return err
+}
+
+func encrypt(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	output := flags.String("o", "", "output file (default: input file + "+crypt.Extension+", - for stdout)")
+
+	var recipients stringList
+
+	flags.Var(&recipients, "recipient", "age recipient (repeatable); default: the "+crypt.EnvKey+" AES key")
+
+	file, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := readYAML(file)
+	if err != nil {
+		return err
+	}
+
+	encrypter, err := newEncrypter(recipients, crypt.FormatAES)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := encrypter.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = file + crypt.Extension
+	}
+
+	return writeOutput(*output, ciphertext, stdout)
+}
+
+func decrypt(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	output := flags.String("o", "-", "output file (- for stdout)")
+
+	file, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+
+	plaintext, _, err := decryptFile(file)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, plaintext, stdout)
+}
+
+func edit(args []string, _ io.Writer) error {
+	flags := flag.NewFlagSet("edit", flag.ContinueOnError)
+
+	var recipients stringList
+
+	flags.Var(&recipients, "recipient", "age recipient (repeatable); required for age profiles")
+
+	file, err := parseFile(flags, args)
+	if err != nil {
+		return err
+	}
+
+	plaintext, format, err := decryptFile(file)
+	if err != nil {
+		return err
+	}
+
+	if format == crypt.FormatAge && len(recipients) == 0 {
+		return fmt.Errorf("edit %s: %w", file, errAgeRecipients)
+	}
+
+	encrypter, err := newEncrypter(recipients, format)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "config-crypt-*"+filepath.Ext(strings.TrimSuffix(file, crypt.Extension)))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(plaintext); err != nil {
+		return errors.Join(err, tmp.Close())
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	editorArgs := append(shelltools.NewQuotedShellArgs(editorCommand()), tmp.Name())
+	editor := exec.Command(editorArgs[0], editorArgs[1:]...) //nolint:gosec // the editor is chosen by the user
+	editor.Stdin, editor.Stdout, editor.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := editor.Run(); err != nil {
+		return fmt.Errorf("editor: %w", err)
+	}
+
+	edited, err := readYAML(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("%w (%s not changed)", err, file)
+	}
+
+	ciphertext, err := encrypter.Encrypt(edited)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, ciphertext, 0o600) //nolint:mnd
+}
+
+// parseFile parses the flags and returns the single file argument.
+func parseFile(flags *flag.FlagSet, args []string) (string, error) {
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+
+	if flags.NArg() != 1 {
+		return "", errUsage
+	}
+
+	return flags.Arg(0), nil
+}
+
+// readYAML reads a file, checking that it is valid YAML.
+func readYAML(file string) ([]byte, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return content, nil
+}
+
+// decryptFile decrypts a file with the environment keyring.
+func decryptFile(file string) ([]byte, crypt.Format, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, crypt.FormatNone, err
+	}
+
+	keyring, err := crypt.KeyringFromEnv()
+	if err != nil {
+		return nil, crypt.FormatNone, err
+	}
+
+	plaintext, err := keyring.Decrypt(content)
+
+	return plaintext, crypt.Detect(content), err
+}
+
+// newEncrypter returns the age recipients, or the environment key for format.
+func newEncrypter(recipients []string, format crypt.Format) (crypt.Encrypter, error) {
+	if len(recipients) > 0 {
+		return crypt.ParseAgeRecipients(strings.Join(recipients, "\n"))
+	}
+
+	keyring, err := crypt.KeyringFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring.Encrypter(format)
+}
+
+func writeOutput(output string, content []byte, stdout io.Writer) error {
+	if output == "-" {
+		_, err := stdout.Write(content)
+		return err
+	}
+
+	return os.WriteFile(output, content, 0o600) //nolint:mnd
+}
+
+// editorCommand returns $EDITOR (which may hold arguments, e.g.
+// "code --wait") or vi.
+func editorCommand() string {
+	if editor := strings.TrimSpace(os.Getenv("EDITOR")); editor != "" {
+		return editor
+	}
+
+	return "vi"
+}
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
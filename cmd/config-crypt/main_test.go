@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/crypt"
+)
+
+const profile = "database:\n  password: old\n"
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Run("aes", func(t *testing.T) {
+		setAESKey(t)
+
+		file := writeProfile(t)
+		runOK(t, "encrypt", file)
+		assertFormat(t, file+crypt.Extension, crypt.FormatAES)
+
+		assert.Equal(t, profile, runOK(t, "decrypt", file+crypt.Extension))
+	})
+
+	t.Run("age", func(t *testing.T) {
+		identity := setAgeIdentity(t)
+
+		file := writeProfile(t)
+		runOK(t, "encrypt", "-recipient", identity.Recipient().String(), file)
+		assertFormat(t, file+crypt.Extension, crypt.FormatAge)
+
+		assert.Equal(t, profile, runOK(t, "decrypt", file+crypt.Extension))
+	})
+
+	t.Run("wrong_key", func(t *testing.T) {
+		setAESKey(t)
+
+		file := writeProfile(t)
+		runOK(t, "encrypt", file)
+		setAESKey(t)
+
+		code, _, stderr := runCommand("decrypt", file+crypt.Extension)
+		assert.Equal(t, 1, code)
+		assert.NotEmpty(t, stderr)
+	})
+}
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test editor is sed")
+	}
+
+	t.Setenv("EDITOR", "sed -i.bak s/old/new/")
+	t.Setenv("TMPDIR", t.TempDir()) // for the edited copy and its sed backup
+
+	t.Run("aes", func(t *testing.T) {
+		setAESKey(t)
+
+		file := writeProfile(t)
+		runOK(t, "encrypt", file)
+		runOK(t, "edit", file+crypt.Extension)
+
+		assertFormat(t, file+crypt.Extension, crypt.FormatAES)
+		assert.Equal(t, strings.Replace(profile, "old", "new", 1), runOK(t, "decrypt", file+crypt.Extension))
+	})
+
+	t.Run("age_keeps_every_recipient", func(t *testing.T) {
+		other, err := crypt.GenerateAgeIdentity()
+		require.NoError(t, err)
+
+		identity := setAgeIdentity(t)
+		recipients := []string{
+			"-recipient", identity.Recipient().String(),
+			"-recipient", other.Recipient().String(),
+		}
+
+		file := writeProfile(t)
+		runOK(t, append(append([]string{"encrypt"}, recipients...), file)...)
+		runOK(t, append(append([]string{"edit"}, recipients...), file+crypt.Extension)...)
+
+		edited := strings.Replace(profile, "old", "new", 1)
+		assert.Equal(t, edited, runOK(t, "decrypt", file+crypt.Extension))
+
+		t.Setenv(crypt.EnvAgeIdentity, other.String())
+		assert.Equal(t, edited, runOK(t, "decrypt", file+crypt.Extension), "the other recipient can still decrypt")
+	})
+
+	t.Run("age_without_recipients_fails", func(t *testing.T) {
+		identity := setAgeIdentity(t)
+
+		file := writeProfile(t)
+		runOK(t, "encrypt", "-recipient", identity.Recipient().String(), file)
+
+		before, err := os.ReadFile(file + crypt.Extension)
+		require.NoError(t, err)
+
+		code, _, stderr := runCommand("edit", file+crypt.Extension)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "-recipient")
+
+		after, err := os.ReadFile(file + crypt.Extension)
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "the file is not changed")
+	})
+}
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{{}, {"unknown"}, {"decrypt"}, {"encrypt", "a", "b"}} {
+		code, _, _ := runCommand(args...)
+		assert.Equal(t, 2, code, args)
+	}
+}
+
+func setAESKey(t *testing.T) {
+	t.Helper()
+
+	key, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	t.Setenv(crypt.EnvKey, key.String())
+}
+
+func setAgeIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+
+	identity, err := crypt.GenerateAgeIdentity()
+	require.NoError(t, err)
+
+	t.Setenv(crypt.EnvAgeIdentity, identity.String())
+
+	return identity
+}
+
+func writeProfile(t *testing.T) string {
+	t.Helper()
+
+	file := path.Join(t.TempDir(), "production.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(profile), 0o600))
+
+	return file
+}
+
+func assertFormat(t *testing.T, file string, format crypt.Format) {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, format, crypt.Detect(content))
+}
+
+func runOK(t *testing.T, args ...string) string {
+	t.Helper()
+
+	code, stdout, stderr := runCommand(args...)
+	require.Equal(t, 0, code, stderr)
+
+	return stdout
+}
+
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
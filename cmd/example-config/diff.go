@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/guionardo/go/config"
+)
+
+// formatText prints the diff as text lines (see config.ScopeDiff.String).
+const formatText = "text"
+
+// runDiff runs the diff subcommand.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("example-config diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: example-config diff [flags] from-scope to-scope")
+		flags.PrintDefaults()
+	}
+
+	profilesPath := flags.String("profiles", config.DefaultConfigurationPath, "YAML profiles directory")
+	defaultScope := flags.String("default-scope", config.DefaultScope, "fallback scope merged below each scope")
+	format := flags.String("format", formatText, "output format: text, yaml or json")
+	fromEnv := flags.String("from-env", "", ".env file applied over the from scope")
+	toEnv := flags.String("to-env", "", ".env file applied over the to scope")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 2 { //nolint:mnd
+		flags.Usage()
+		return 2
+	}
+
+	if *format != formatText && config.Format(*format) != config.FormatYAML && config.Format(*format) != config.FormatJSON {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	if _, err := os.Stat(*profilesPath); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	diff, err := config.DiffScopes[AppConfig](
+		config.ScopeTarget{Scope: flags.Arg(0), EnvFile: *fromEnv},
+		config.ScopeTarget{Scope: flags.Arg(1), EnvFile: *toEnv},
+		config.WithProfilesPath(*profilesPath),
+		config.WithDefaultScope(*defaultScope))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if *format == formatText {
+		fmt.Fprint(stdout, diff)
+	} else if err := diff.Write(stdout, config.Format(*format)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if diff.Empty() {
+		return 0
+	}
+
+	return 1
+}
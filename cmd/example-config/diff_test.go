@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDiff(t *testing.T) {
+	t.Parallel()
+
+	profiles := writeProfiles(t)
+
+	envFile := path.Join(t.TempDir(), "staging.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("APP_NAME=other\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "identical",
+			args:       []string{"-profiles", profiles, "default", "staging"},
+			wantStdout: []string{"--- default", "+++ staging"},
+		},
+		{
+			name:       "different",
+			args:       []string{"-profiles", profiles, "default", "production"},
+			wantCode:   1,
+			wantStdout: []string{"--- default", "+++ production", "name", "database.password", "********"},
+		},
+		{
+			name:       "different_json",
+			args:       []string{"-profiles", profiles, "-format", "json", "default", "production"},
+			wantCode:   1,
+			wantStdout: []string{`"********"`},
+		},
+		{
+			name:       "env_file",
+			args:       []string{"-profiles", profiles, "-to-env", envFile, "default", "staging"},
+			wantCode:   1,
+			wantStdout: []string{"other"},
+		},
+		{
+			name:       "missing_scope",
+			args:       []string{"-profiles", profiles, "default", "missing"},
+			wantCode:   2,
+			wantStderr: []string{"scope missing: profile: profile file not found"},
+		},
+		{
+			name:       "invalid_value",
+			args:       []string{"-profiles", profiles, "default", "broken"},
+			wantCode:   2,
+			wantStderr: []string{"scope broken:"},
+		},
+		{
+			name:       "missing_env_file",
+			args:       []string{"-profiles", profiles, "-from-env", path.Join(profiles, "missing.env"), "default", "staging"},
+			wantCode:   2,
+			wantStderr: []string{"scope default: env:", "missing.env"},
+		},
+		{
+			name:       "unreadable_env_file",
+			args:       []string{"-profiles", profiles, "-to-env", profiles, "default", "staging"},
+			wantCode:   2,
+			wantStderr: []string{"scope staging: env:"},
+		},
+		{
+			name:       "one_scope",
+			args:       []string{"-profiles", profiles, "default"},
+			wantCode:   2,
+			wantStderr: []string{"usage: example-config diff"},
+		},
+		{
+			name:       "unknown_format",
+			args:       []string{"-profiles", profiles, "-format", "toml", "default", "staging"},
+			wantCode:   2,
+			wantStderr: []string{`unknown format "toml"`},
+		},
+		{
+			name:     "missing_profiles",
+			args:     []string{"-profiles", path.Join(profiles, "missing"), "default", "staging"},
+			wantCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, stdout, stderr := runCommand(append([]string{"diff"}, tt.args...)...)
+			assert.Equal(t, tt.wantCode, code, stderr)
+			assert.NotContains(t, stdout, secret, "safe values are masked")
+
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout, want)
+			}
+
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr, want)
+			}
+		})
+	}
+}
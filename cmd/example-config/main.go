@@ -0,0 +1,119 @@
+// Package main provides an example CLI demonstrating config.Export: it prints
+// the effective configuration of each scope (profiles, sources, secrets and
+// environment merged, secrets masked) and validates it.
+//
+// Copy it into your application and replace AppConfig (and the provider
+// options) with your own, then run it in CI for every scope you deploy:
+//
+//	go run ./cmd/example-config -profiles CONFIGS -format yaml production staging
+//
+// With -env-template it prints a ready-to-fill .env template of the
+// environment variables of AppConfig instead:
+//
+//	go run ./cmd/example-config -env-template > .env
+//
+// It exits:
+//   - 0 if every scope loaded and validated
+//   - 1 if any scope failed to load or validate (the configuration is still printed)
+//   - 2 on usage errors
+//
+// The diff subcommand compares two scopes with config.DiffScopes, e.g.
+// before promoting staging settings to production, printing the keys
+// added, removed and changed (`safe` values masked); each scope may get a
+// .env file:
+//
+//	go run ./cmd/example-config diff -profiles CONFIGS -to-env production.env staging production
+//
+// It exits 0 when the scopes are the same, 1 when they differ and 2 on
+// errors, like diff(1).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/guionardo/go/config"
+)
+
+type (
+	// AppConfig is the configuration struct checked by this example.
+	AppConfig struct {
+		Name     string `yaml:"name" env:"APP_NAME" validate:"required"`
+		Port     int    `yaml:"port" env:"APP_PORT" default:"8080" validate:"min=1,max=65535"`
+		Database struct {
+			Host     string `yaml:"host" validate:"required"`
+			Password string `yaml:"password" safe:"true"`
+		} `yaml:"database"`
+	}
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "diff" {
+		return runDiff(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("example-config", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: example-config [flags] scope...\n       example-config diff [flags] from-scope to-scope")
+		flags.PrintDefaults()
+	}
+
+	profilesPath := flags.String("profiles", config.DefaultConfigurationPath, "YAML profiles directory")
+	defaultScope := flags.String("default-scope", config.DefaultScope, "fallback scope merged below each scope")
+	format := flags.String("format", string(config.FormatYAML), "output format: yaml or json")
+	envTemplate := flags.Bool("env-template", false, "print a .env template of the environment variables and exit")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *envTemplate {
+		if err := config.WriteEnvTemplate[AppConfig](stdout); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		return 0
+	}
+
+	if config.Format(*format) != config.FormatYAML && config.Format(*format) != config.FormatJSON {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	if _, err := os.Stat(*profilesPath); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	scopes := flags.Args()
+	if len(scopes) == 0 {
+		scopes = []string{*defaultScope}
+	}
+
+	exitCode := 0
+
+	for _, scope := range scopes {
+		if config.Format(*format) == config.FormatYAML {
+			fmt.Fprintf(stdout, "---\n# scope: %s\n", scope)
+		}
+
+		err := config.Export[AppConfig](stdout, config.Format(*format),
+			config.WithProfilesPath(*profilesPath),
+			config.WithDefaultScope(*defaultScope),
+			config.WithScope(scope))
+		if err != nil {
+			fmt.Fprintf(stderr, "scope %s: %v\n", scope, err)
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "s3cret"
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	profiles := writeProfiles(t)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "yaml",
+			args:       []string{"-profiles", profiles, "production"},
+			wantStdout: []string{"# scope: production", "name: prod", "host: db", "password: '********'"},
+		},
+		{
+			name:       "json",
+			args:       []string{"-profiles", profiles, "-format", "json", "production"},
+			wantStdout: []string{`"name": "prod"`, `"password": "********"`},
+		},
+		{
+			name:       "default_scope",
+			args:       []string{"-profiles", profiles},
+			wantStdout: []string{"# scope: default", "name: app"},
+		},
+		{
+			name:       "env_template",
+			args:       []string{"-env-template"},
+			wantStdout: []string{"APP_NAME=", "# APP_PORT=8080"},
+		},
+		{
+			name:       "unknown_flag",
+			args:       []string{"-unknown"},
+			wantCode:   2,
+			wantStderr: []string{"usage: example-config"},
+		},
+		{
+			name:       "unknown_format",
+			args:       []string{"-profiles", profiles, "-format", "toml", "production"},
+			wantCode:   2,
+			wantStderr: []string{`unknown format "toml"`},
+		},
+		{
+			name:     "missing_profiles",
+			args:     []string{"-profiles", path.Join(profiles, "missing")},
+			wantCode: 2,
+		},
+		{
+			name:       "missing_scope",
+			args:       []string{"-profiles", profiles, "production", "missing"},
+			wantCode:   1,
+			wantStdout: []string{"# scope: production", "# scope: missing"},
+			wantStderr: []string{"scope missing: profile: profile file not found"},
+		},
+		{
+			name:       "invalid_value",
+			args:       []string{"-profiles", profiles, "broken"},
+			wantCode:   1,
+			wantStdout: []string{"# scope: broken"},
+			wantStderr: []string{"scope broken:", "cannot unmarshal"},
+		},
+		{
+			name:       "validation_error",
+			args:       []string{"-profiles", profiles, "-default-scope", "production", "production"},
+			wantCode:   1,
+			wantStdout: []string{"name: prod"},
+			wantStderr: []string{"scope production:", "Database.Host is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, stdout, stderr := runCommand(tt.args...)
+			assert.Equal(t, tt.wantCode, code, stderr)
+			assert.NotContains(t, stdout, secret, "safe values are masked")
+
+			for _, want := range tt.wantStdout {
+				assert.Contains(t, stdout, want)
+			}
+
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr, want)
+			}
+		})
+	}
+}
+
+// writeProfiles writes the test profiles directory: default holds every
+// field, production and staging override it (staging with the same values)
+// and broken has a port that is not a number.
+func writeProfiles(t *testing.T) string {
+	t.Helper()
+
+	profiles := t.TempDir()
+
+	for name, content := range map[string]string{
+		"default.yaml":    "name: app\ndatabase:\n  host: db\n  password: " + secret + "\n",
+		"production.yaml": "name: prod\ndatabase:\n  password: t0p-" + secret + "\n",
+		"staging.yaml":    "name: app\n",
+		"broken.yaml":     "port: http\n",
+	} {
+		require.NoError(t, os.WriteFile(path.Join(profiles, name), []byte(content), 0o600))
+	}
+
+	return profiles
+}
+
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"log/slog"
 	"os"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/source"
 )
 
 type testConfig struct {
@@ -171,6 +174,45 @@ func TestWithLogger(t *testing.T) {
 	assert.Equal(t, logger, p.logger)
 }
 
+type loggedConfig struct {
+	Name     string `yaml:"name" env:"LOGGED_NAME" validate:"required"`
+	Password string `yaml:"password" safe:"true" validate:"min=10"`
+	Token    string `yaml:"token" validate:"min=10"`
+}
+
+func TestWithLogger_Events(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"),
+		[]byte("password: hunter2\ntoken: ${env:LOGGED_TOKEN}"), 0o600))
+	require.NoError(t, os.WriteFile(path.Join(tmp, ".env"), []byte("LOGGED_NAME=app\nLOGGED_TOKEN=s3cret"), 0o600))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	provider := NewProvider[loggedConfig](
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("default"),
+		WithLogger(logger),
+		WithSources(source.Profiles, source.DotEnv(path.Join(tmp, ".env")), source.Environment),
+	)
+
+	_, err := provider.GetConfiguration()
+	require.Error(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `msg="configuration provider initialized"`)
+	assert.Contains(t, logs, `msg="profile found" file=`+path.Join(tmp, "default.yml"))
+	assert.Contains(t, logs, `msg="environment variable applied" field=Name env=LOGGED_NAME`)
+	assert.Contains(t, logs, `msg="configuration validation failed" fields.Password.tag=min fields.Password.param=10`)
+	assert.Contains(t, logs, `fields.Password.value=********`)
+	assert.Contains(t, logs, `fields.Token.value=********`)
+	assert.NotContains(t, logs, "hunter2")
+	assert.NotContains(t, logs, "s3cret")
+}
+
 func TestWithDebugLogger(t *testing.T) {
 	opt := WithDebugLogger()
 	require.NotNil(t, opt)
@@ -202,6 +244,19 @@ func TestWithDefaultScope(t *testing.T) {
 	assert.Equal(t, "base", p.defaultScope)
 }
 
+func TestWithEnvNaming(t *testing.T) {
+	t.Setenv("MYAPP_TESTCFG_NAME", "prefixed")
+	t.Setenv("MYAPP_NESTED_TAGS", "a,b")
+	t.Setenv("MYAPP_SECRET", "s3cr3t")
+
+	provider := NewProvider[testConfig](WithEnvPrefix("MYAPP_"), WithEnvAutoNaming())
+	cfg, err := provider.GetConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "prefixed", cfg.Name)
+	assert.Equal(t, "a,b", cfg.Nested.Tags)
+	assert.Equal(t, "s3cr3t", cfg.Secret)
+}
+
 func TestProviderGetConfiguration_ProfileError(t *testing.T) {
 	t.Run("invalid_yaml_in_profile", func(t *testing.T) {
 		tmp := t.TempDir()
@@ -1,6 +1,11 @@
 package config
 
+import "time"
+
 const (
+	// DefaultWatchInterval is how often Watch polls the profile files by default.
+	DefaultWatchInterval = 2 * time.Second
+
 	// DefaultScope is the default configuration scope name used when none is specified.
 	DefaultScope = "default"
 
@@ -0,0 +1,363 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+type (
+	// Encrypter encrypts profile contents.
+	Encrypter interface {
+		Encrypt(plaintext []byte) ([]byte, error)
+	}
+
+	// Decrypter decrypts profile contents.
+	Decrypter interface {
+		Decrypt(ciphertext []byte) ([]byte, error)
+	}
+
+	// AESKey is a 256-bit AES-GCM key. Files are written as the aesHeader
+	// line followed by the base64 of nonce and sealed content.
+	AESKey []byte
+
+	// AgeRecipients encrypts to age public keys ("age1...").
+	AgeRecipients []age.Recipient
+
+	// AgeIdentities decrypts age files with age secret keys
+	// ("AGE-SECRET-KEY-1...").
+	AgeIdentities []age.Identity
+
+	// Keyring holds the keys found in the environment and decrypts both
+	// formats.
+	Keyring struct {
+		AES []AESKey
+		Age AgeIdentities
+	}
+)
+
+const (
+	// Extension is the suffix of encrypted profile files
+	// ("production.yaml.enc").
+	Extension = ".enc"
+
+	// EnvKey holds a base64 or hex AES key.
+	EnvKey = "CONFIG_KEY"
+	// EnvKeyFile is the path of a file holding a base64 or hex AES key.
+	EnvKeyFile = "CONFIG_KEY_FILE"
+	// EnvAgeIdentity holds age secret keys, one per line.
+	EnvAgeIdentity = "CONFIG_AGE_IDENTITY"
+	// EnvAgeIdentityFile is the path of an age identity file.
+	EnvAgeIdentityFile = "CONFIG_AGE_IDENTITY_FILE"
+
+	// AESKeySize is the length of an AESKey.
+	AESKeySize = 32
+
+	aesHeader     = "guionardo/config-aes-gcm/v1"
+	ageHeader     = "age-encryption.org/v1"
+	base64LineLen = 64
+)
+
+var (
+	// ErrNoKey is returned when there is no key for an encrypted content.
+	ErrNoKey = errors.New("no decryption key")
+	// ErrUnknownFormat is returned for content that is neither AES-GCM nor age.
+	ErrUnknownFormat = errors.New("unknown encrypted format")
+	// ErrInvalidKey is returned for a key that cannot be parsed.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// GenerateAESKey returns a random AES key.
+func GenerateAESKey() (AESKey, error) {
+	key := make(AESKey, AESKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	return key, nil
+}
+
+// ParseAESKey parses a base64 (standard or URL) or hex AES key.
+func ParseAESKey(s string) (AESKey, error) {
+	s = strings.TrimSpace(s)
+
+	for _, decode := range []func(string) ([]byte, error){
+		base64.StdEncoding.DecodeString,
+		base64.URLEncoding.DecodeString,
+		base64.RawStdEncoding.DecodeString,
+		hex.DecodeString,
+	} {
+		if key, err := decode(s); err == nil && len(key) == AESKeySize {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("config/crypt: %w: expected %d bytes in base64 or hex", ErrInvalidKey, AESKeySize)
+}
+
+// String returns the key in base64.
+func (k AESKey) String() string {
+	return base64.StdEncoding.EncodeToString(k)
+}
+
+// Encrypt implements Encrypter.
+func (k AESKey) Encrypt(plaintext []byte) ([]byte, error) {
+	gcm, err := k.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, []byte(aesHeader))
+	encoded := base64.StdEncoding.EncodeToString(sealed)
+
+	var out bytes.Buffer
+
+	out.WriteString(aesHeader + "\n")
+
+	for len(encoded) > 0 {
+		n := min(base64LineLen, len(encoded))
+		out.WriteString(encoded[:n] + "\n")
+		encoded = encoded[n:]
+	}
+
+	return out.Bytes(), nil
+}
+
+// Decrypt implements Decrypter.
+func (k AESKey) Decrypt(ciphertext []byte) ([]byte, error) {
+	body, ok := bytes.CutPrefix(bytes.TrimSpace(ciphertext), []byte(aesHeader+"\n"))
+	if !ok {
+		return nil, fmt.Errorf("config/crypt: %w: missing %q header", ErrUnknownFormat, aesHeader)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	gcm, err := k.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("config/crypt: %w: content too short", ErrUnknownFormat)
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(aesHeader))
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func (k AESKey) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w: %w", ErrInvalidKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// ParseAgeRecipients parses age public keys ("age1..."), one per line or
+// separated by commas; blank lines and # comments are ignored.
+func ParseAgeRecipients(s string) (AgeRecipients, error) {
+	recipients, err := age.ParseRecipients(strings.NewReader(strings.ReplaceAll(s, ",", "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w: %w", ErrInvalidKey, err)
+	}
+
+	return recipients, nil
+}
+
+// Encrypt implements Encrypter, writing ASCII-armored age files.
+func (r AgeRecipients) Encrypt(plaintext []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	armorWriter := armor.NewWriter(&out)
+
+	writer, err := age.Encrypt(armorWriter, r...)
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	if _, err := writer.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	if err := errors.Join(writer.Close(), armorWriter.Close()); err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// GenerateAgeIdentity returns a new age X25519 identity.
+func GenerateAgeIdentity() (*age.X25519Identity, error) {
+	return age.GenerateX25519Identity()
+}
+
+// ParseAgeIdentities parses age secret keys in the identity file format.
+func ParseAgeIdentities(s string) (AgeIdentities, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(s))
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w: %w", ErrInvalidKey, err)
+	}
+
+	return identities, nil
+}
+
+// Decrypt implements Decrypter, for binary and ASCII-armored age files.
+func (i AgeIdentities) Decrypt(ciphertext []byte) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(bytes.TrimSpace(ciphertext)))
+	}
+
+	reader, err := age.Decrypt(src, i...)
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("config/crypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// Recipients returns the recipients of the X25519 identities, to encrypt
+// again what they decrypt.
+func (i AgeIdentities) Recipients() AgeRecipients {
+	var recipients AgeRecipients
+
+	for _, identity := range i {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			recipients = append(recipients, x25519.Recipient())
+		}
+	}
+
+	return recipients
+}
+
+// KeyringFromEnv reads the keys of EnvKey, EnvKeyFile, EnvAgeIdentity and
+// EnvAgeIdentityFile. Variables that are not set are ignored.
+func KeyringFromEnv() (*Keyring, error) {
+	keyring := &Keyring{}
+
+	var errs []error
+
+	for _, source := range []struct {
+		env    string
+		isFile bool
+		add    func(string) error
+	}{
+		{EnvKey, false, keyring.addAES},
+		{EnvKeyFile, true, keyring.addAES},
+		{EnvAgeIdentity, false, keyring.addAge},
+		{EnvAgeIdentityFile, true, keyring.addAge},
+	} {
+		value, ok := os.LookupEnv(source.env)
+		if !ok || value == "" {
+			continue
+		}
+
+		if source.isFile {
+			content, err := os.ReadFile(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("config/crypt: %s: %w", source.env, err))
+				continue
+			}
+
+			value = string(content)
+		}
+
+		if err := source.add(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.env, err))
+		}
+	}
+
+	return keyring, errors.Join(errs...)
+}
+
+func (k *Keyring) addAES(s string) error {
+	key, err := ParseAESKey(s)
+	if err == nil {
+		k.AES = append(k.AES, key)
+	}
+
+	return err
+}
+
+func (k *Keyring) addAge(s string) error {
+	identities, err := ParseAgeIdentities(s)
+	if err == nil {
+		k.Age = append(k.Age, identities...)
+	}
+
+	return err
+}
+
+// Decrypt implements Decrypter: it detects the format and tries the keys
+// of that format.
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	switch Detect(ciphertext) {
+	case FormatAES:
+		if len(k.AES) == 0 {
+			return nil, fmt.Errorf("config/crypt: %w: set %s or %s", ErrNoKey, EnvKey, EnvKeyFile)
+		}
+
+		var errs []error
+
+		for _, key := range k.AES {
+			plaintext, err := key.Decrypt(ciphertext)
+			if err == nil {
+				return plaintext, nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		return nil, errors.Join(errs...)
+	case FormatAge:
+		if len(k.Age) == 0 {
+			return nil, fmt.Errorf("config/crypt: %w: set %s or %s", ErrNoKey, EnvAgeIdentity, EnvAgeIdentityFile)
+		}
+
+		return k.Age.Decrypt(ciphertext)
+	default:
+		return nil, fmt.Errorf("config/crypt: %w", ErrUnknownFormat)
+	}
+}
+
+// Encrypter returns an Encrypter for content of the given format: the
+// first AES key, or the recipients of the age identities.
+func (k *Keyring) Encrypter(format Format) (Encrypter, error) {
+	switch {
+	case format == FormatAES && len(k.AES) > 0:
+		return k.AES[0], nil
+	case format == FormatAge && len(k.Age.Recipients()) > 0:
+		return k.Age.Recipients(), nil
+	default:
+		return nil, fmt.Errorf("config/crypt: %w for %s", ErrNoKey, format)
+	}
+}
@@ -0,0 +1,143 @@
+package crypt_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/crypt"
+)
+
+const profile = "database:\n  password: s3cr3t\n"
+
+func TestAESKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+	require.Len(t, key, crypt.AESKeySize)
+
+	ciphertext, err := key.Encrypt([]byte(profile))
+	require.NoError(t, err)
+	assert.Equal(t, crypt.FormatAES, crypt.Detect(ciphertext))
+	assert.NotContains(t, string(ciphertext), "s3cr3t")
+
+	plaintext, err := key.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, profile, string(plaintext))
+
+	other, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(ciphertext)
+	require.Error(t, err)
+
+	_, err = key.Decrypt([]byte(profile))
+	require.ErrorIs(t, err, crypt.ErrUnknownFormat)
+
+	tampered := []byte(strings.Replace(string(ciphertext), "\n", "\nAAAA", 1))
+	_, err = key.Decrypt(tampered)
+	require.Error(t, err)
+}
+
+func TestParseAESKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	for _, encoded := range []string{key.String(), hex.EncodeToString(key), " " + key.String() + "\n"} {
+		parsed, err := crypt.ParseAESKey(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, key, parsed)
+	}
+
+	_, err = crypt.ParseAESKey("c2hvcnQ=")
+	require.ErrorIs(t, err, crypt.ErrInvalidKey)
+}
+
+func TestAge(t *testing.T) {
+	t.Parallel()
+
+	identity, err := crypt.GenerateAgeIdentity()
+	require.NoError(t, err)
+
+	recipients, err := crypt.ParseAgeRecipients(identity.Recipient().String())
+	require.NoError(t, err)
+
+	ciphertext, err := recipients.Encrypt([]byte(profile))
+	require.NoError(t, err)
+	assert.Equal(t, crypt.FormatAge, crypt.Detect(ciphertext))
+
+	identities, err := crypt.ParseAgeIdentities(identity.String())
+	require.NoError(t, err)
+
+	plaintext, err := identities.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, profile, string(plaintext))
+	assert.Equal(t, identity.Recipient().String(), identities.Recipients()[0].(interface{ String() string }).String())
+
+	_, err = crypt.ParseAgeRecipients("not-a-recipient")
+	require.ErrorIs(t, err, crypt.ErrInvalidKey)
+	_, err = crypt.ParseAgeIdentities("not-an-identity")
+	require.ErrorIs(t, err, crypt.ErrInvalidKey)
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, crypt.FormatNone, crypt.Detect([]byte(profile)))
+	assert.Equal(t, "none", crypt.FormatNone.String())
+	assert.Equal(t, crypt.FormatAge, crypt.Detect([]byte("age-encryption.org/v1\n-> X25519 ...")))
+}
+
+func TestKeyringFromEnv(t *testing.T) {
+	aesKey, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	identity, err := crypt.GenerateAgeIdentity()
+	require.NoError(t, err)
+
+	identityFile := path.Join(t.TempDir(), "identity.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte("# test\n"+identity.String()+"\n"), 0o600))
+
+	t.Setenv(crypt.EnvKey, aesKey.String())
+	t.Setenv(crypt.EnvKeyFile, "")
+	t.Setenv(crypt.EnvAgeIdentity, "")
+	t.Setenv(crypt.EnvAgeIdentityFile, identityFile)
+
+	keyring, err := crypt.KeyringFromEnv()
+	require.NoError(t, err)
+
+	for _, format := range []crypt.Format{crypt.FormatAES, crypt.FormatAge} {
+		encrypter, err := keyring.Encrypter(format)
+		require.NoError(t, err)
+
+		ciphertext, err := encrypter.Encrypt([]byte(profile))
+		require.NoError(t, err)
+		assert.Equal(t, format, crypt.Detect(ciphertext))
+
+		plaintext, err := keyring.Decrypt(ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, profile, string(plaintext))
+	}
+
+	_, err = (&crypt.Keyring{}).Decrypt([]byte("age-encryption.org/v1\n"))
+	require.ErrorIs(t, err, crypt.ErrNoKey)
+	_, err = keyring.Decrypt([]byte(profile))
+	require.ErrorIs(t, err, crypt.ErrUnknownFormat)
+	_, err = (&crypt.Keyring{}).Encrypter(crypt.FormatAES)
+	require.ErrorIs(t, err, crypt.ErrNoKey)
+
+	t.Setenv(crypt.EnvKey, "invalid")
+	t.Setenv(crypt.EnvAgeIdentityFile, path.Join(t.TempDir(), "missing"))
+
+	_, err = crypt.KeyringFromEnv()
+	require.ErrorIs(t, err, crypt.ErrInvalidKey)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
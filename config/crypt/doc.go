@@ -0,0 +1,23 @@
+// Package crypt encrypts configuration profiles so they can live in the
+// repository next to the plain ones ("production.yaml.enc").
+//
+// Two formats are supported, detected from the file header:
+//   - AES-256-GCM with a shared AESKey, written as a header line and
+//     base64 text (FormatAES)
+//   - age files for X25519 recipients, ASCII-armored (FormatAge): encrypt
+//     to the public keys of everyone allowed to read, decrypt with any
+//     matching identity
+//
+// Keys are usually supplied through the environment and read by
+// KeyringFromEnv: CONFIG_KEY or CONFIG_KEY_FILE for the AES key,
+// CONFIG_AGE_IDENTITY or CONFIG_AGE_IDENTITY_FILE for age identities.
+// config.Provider uses that keyring unless config.WithDecrypter is set.
+//
+// Usage:
+//
+//	key, err := crypt.GenerateAESKey()            // keep key.String() secret
+//	ciphertext, err := key.Encrypt(plaintext)      // write production.yaml.enc
+//
+// The cmd/config-crypt CLI generates keys and encrypts, decrypts and edits
+// profiles.
+package crypt
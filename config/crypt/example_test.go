@@ -0,0 +1,20 @@
+package crypt_test
+
+import (
+	"fmt"
+
+	"github.com/guionardo/go/config/crypt"
+)
+
+func ExampleAESKey() {
+	key, _ := crypt.GenerateAESKey() // store key.String() in CONFIG_KEY
+
+	ciphertext, _ := key.Encrypt([]byte("password: s3cr3t\n"))
+	fmt.Println(crypt.Detect(ciphertext))
+
+	plaintext, _ := key.Decrypt(ciphertext)
+	fmt.Print(string(plaintext))
+	// Output:
+	// aes-gcm
+	// password: s3cr3t
+}
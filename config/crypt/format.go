@@ -0,0 +1,44 @@
+package crypt
+
+import (
+	"bytes"
+
+	"filippo.io/age/armor"
+)
+
+type (
+	// Format is the encryption format of a content.
+	Format string
+)
+
+const (
+	// FormatNone is plain (or unrecognized) content.
+	FormatNone Format = ""
+	// FormatAES is AESKey content.
+	FormatAES Format = "aes-gcm"
+	// FormatAge is an age file, binary or ASCII-armored.
+	FormatAge Format = "age"
+)
+
+// Detect returns the format of content from its header.
+func Detect(content []byte) Format {
+	trimmed := bytes.TrimSpace(content)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(aesHeader+"\n")):
+		return FormatAES
+	case bytes.HasPrefix(trimmed, []byte(ageHeader)), bytes.HasPrefix(trimmed, []byte(armor.Header)):
+		return FormatAge
+	default:
+		return FormatNone
+	}
+}
+
+// String returns the format name ("none" for FormatNone).
+func (f Format) String() string {
+	if f == FormatNone {
+		return "none"
+	}
+
+	return string(f)
+}
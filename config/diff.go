@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type (
+	// FieldChange is a single changed configuration field.
+	// Old and New are masked for `safe`-tagged fields.
+	FieldChange struct {
+		Path string
+		Old  any
+		New  any
+	}
+
+	// Changes is the list of changed fields between two configurations,
+	// sorted by path.
+	Changes []FieldChange
+)
+
+// maskedValue replaces the value of `safe`-tagged fields in logs and diffs.
+const maskedValue = "********"
+
+// Diff compares two configurations field by field, using the same dotted
+// field paths as the configuration log (e.g. "Database.Host").
+// Values of `safe`-tagged fields are masked; their changes are still reported.
+func Diff[T any](old, new T) Changes {
+	oldFields := flattenFields(old)
+	newFields := flattenFields(new)
+
+	var changes Changes
+
+	for path, oldField := range oldFields {
+		newField := newFields[path]
+		if reflect.DeepEqual(oldField.value, newField.value) {
+			continue
+		}
+
+		change := FieldChange{Path: path, Old: oldField.value, New: newField.value}
+		if oldField.safe {
+			change.Old, change.New = maskedValue, maskedValue
+		}
+
+		changes = append(changes, change)
+	}
+
+	slices.SortFunc(changes, func(a, b FieldChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return changes
+}
+
+// Diff returns the changed fields between Old and New.
+func (c Change[T]) Diff() Changes {
+	return Diff(c.Old, c.New)
+}
+
+// Paths returns the changed field paths.
+func (c Changes) Paths() []string {
+	paths := make([]string, len(c))
+	for i, change := range c {
+		paths[i] = change.Path
+	}
+
+	return paths
+}
+
+// Touches reports whether any change is at path or below it,
+// e.g. Touches("Database") is true when "Database.Host" changed.
+func (c Changes) Touches(path string) bool {
+	for _, change := range c {
+		if change.Path == path || strings.HasPrefix(change.Path, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// masked returns a copy of the changes with the values at or below the
+// given field paths masked.
+func (c Changes) masked(paths []string) Changes {
+	if len(paths) == 0 {
+		return c
+	}
+
+	masked := slices.Clone(c)
+	for i, change := range masked {
+		if isMaskedPath(change.Path, paths) {
+			masked[i].Old, masked[i].New = maskedValue, maskedValue
+		}
+	}
+
+	return masked
+}
+
+// String formats the change as "Path: old -> new".
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+type flatField struct {
+	value any
+	safe  bool
+}
+
+// flattenFields maps every leaf field path to its raw value, flagging
+// `safe`-tagged fields. Safe struct fields are not descended into.
+func flattenFields(c any) map[string]flatField {
+	fields := map[string]flatField{}
+	walkFields(reflect.ValueOf(c), "", func(path string, value any, safe bool) {
+		fields[path] = flatField{value: value, safe: safe}
+	})
+
+	return fields
+}
+
+// walkFields visits the leaf fields of a struct value with their dotted path.
+// Non-struct values, and structs without exported fields such as time.Time,
+// are visited once under parentPath. Unexported fields are skipped.
+func walkFields(v reflect.Value, parentPath string, visit func(path string, value any, safe bool)) {
+	if !isFieldStruct(v.Type()) {
+		visit(parentPath, v.Interface(), false)
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := fieldPath(parentPath, field.Name)
+
+		if _, safe := field.Tag.Lookup("safe"); safe {
+			visit(path, v.Field(i).Interface(), true)
+			continue
+		}
+
+		if isFieldStruct(field.Type) {
+			walkFields(v.Field(i), path, visit)
+			continue
+		}
+
+		visit(path, v.Field(i).Interface(), false)
+	}
+}
+
+// isFieldStruct reports whether t is a struct with exported fields to walk.
+func isFieldStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_changes", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := testConfig{Name: "a", Version: 1}
+		assert.Empty(t, Diff(cfg, cfg))
+	})
+
+	t.Run("changed_fields_sorted_by_path", func(t *testing.T) {
+		t.Parallel()
+
+		old := testConfig{Name: "a", Version: 1, Nested: testSubConfig{Tags: "x"}}
+		updated := testConfig{Name: "b", Version: 1, Nested: testSubConfig{Tags: "y"}}
+
+		changes := Diff(old, updated)
+		assert.Equal(t, Changes{
+			{Path: "Name", Old: "a", New: "b"},
+			{Path: "Nested.Tags", Old: "x", New: "y"},
+		}, changes)
+		assert.Equal(t, []string{"Name", "Nested.Tags"}, changes.Paths())
+	})
+
+	t.Run("safe_fields_are_masked", func(t *testing.T) {
+		t.Parallel()
+
+		changes := Diff(testConfig{Secret: "old-secret"}, testConfig{Secret: "new-secret"})
+		assert.Equal(t, Changes{{Path: "Secret", Old: maskedValue, New: maskedValue}}, changes)
+		assert.Equal(t, "Secret: ******** -> ********", changes[0].String())
+	})
+
+	t.Run("touches", func(t *testing.T) {
+		t.Parallel()
+
+		changes := Diff(testConfig{}, testConfig{Nested: testSubConfig{Enabled: true}})
+		assert.True(t, changes.Touches("Nested"))
+		assert.True(t, changes.Touches("Nested.Enabled"))
+		assert.False(t, changes.Touches("Nest"))
+		assert.False(t, changes.Touches("Name"))
+	})
+
+	t.Run("change_diff", func(t *testing.T) {
+		t.Parallel()
+
+		change := Change[testConfig]{Old: testConfig{Version: 1}, New: testConfig{Version: 2}}
+		assert.Equal(t, []string{"Version"}, change.Diff().Paths())
+	})
+}
+
+func TestProviderSubscribe(t *testing.T) {
+	provider := NewProvider[testConfig]()
+
+	var got []Change[testConfig]
+
+	unsubscribe := provider.Subscribe(func(old, new testConfig) {
+		got = append(got, Change[testConfig]{Old: old, New: new})
+	})
+
+	require.NoError(t, provider.UpdateConfiguration(testConfig{Name: "first"}))
+	require.NoError(t, provider.UpdateConfiguration(testConfig{Name: "first"}), "same config must not notify")
+	require.Error(t, provider.UpdateConfiguration(testConfig{}), "invalid config must not notify")
+	require.NoError(t, provider.UpdateConfiguration(testConfig{Name: "second"}))
+
+	unsubscribe()
+	unsubscribe()
+	require.NoError(t, provider.UpdateConfiguration(testConfig{Name: "third"}))
+
+	require.Len(t, got, 2)
+	assert.Empty(t, got[0].Old.Name)
+	assert.Equal(t, "first", got[0].New.Name)
+	assert.Equal(t, "first", got[1].Old.Name)
+	assert.Equal(t, "second", got[1].New.Name)
+}
+
+func TestProviderSubscribe_Reload(t *testing.T) {
+	provider, profilePath := newWatchedProvider(t, "name: first")
+
+	changed := make(chan Changes, 1)
+	provider.Subscribe(func(old, new testConfig) {
+		changed <- Diff(old, new)
+	})
+
+	writeProfile(t, profilePath, "name: first\nsecret: s3cr3t")
+	_, err := provider.reload(t.Context())
+	require.NoError(t, err)
+
+	changes := <-changed
+	assert.Equal(t, Changes{{Path: "Secret", Old: maskedValue, New: maskedValue}}, changes)
+}
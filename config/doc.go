@@ -14,16 +14,131 @@
 //	cfg, err := p.GetConfiguration()
 //	err = p.UpdateConfiguration(cfg)
 //
+// Context-aware loading: Load and Reload fail on any load error with a
+// *LoadError, MustGet panics (for startup):
+//
+//	cfg, err := p.Load(ctx)
+//	cfg, err = p.Reload(ctx)
+//	cfg = p.MustGet()
+//
+// History: every validated configuration (load, reload, update, rollback)
+// is kept as a versioned Snapshot, up to WithHistorySize; Rollback restores
+// one without restarting:
+//
+//	for _, s := range p.History() {
+//	    log.Printf("v%d %s %s", s.Version, s.Source, s.Time)
+//	}
+//	err = p.Rollback(version)
+//
+// Hot-reload:
+//
+//	for change := range p.Watch(ctx) {
+//	    log.Printf("config changed: %v", change.Diff())
+//	}
+//
+// Change subscriptions (UpdateConfiguration and reloads):
+//
+//	unsubscribe := p.Subscribe(func(old, new AppConfig) {
+//	    if config.Diff(old, new).Touches("Database") {
+//	        reconnect(new.Database)
+//	    }
+//	})
+//
 // Options:
 //   - WithProfilesPath: set YAML profile directory
 //   - WithScope: set active scope name
 //   - WithDefaultScope: set fallback scope
-//   - WithLogger: inject custom logger
+//   - WithProfileRequired: fail instead of warning on a missing profile
+//   - WithLogger: inject custom logger, also used for profile loading and
+//     environment parsing
 //   - WithDebugLogger: enable debug logging
+//   - WithWatchInterval: set the Watch polling interval (profiles and
+//     versioned sources such as source.HTTP)
+//   - WithHistorySize: number of configuration snapshots kept for Rollback
+//   - WithEnvPrefix: prefix every environment variable name (e.g. "MYAPP_")
+//   - WithEnvAutoNaming: derive env names from field paths (DATABASE_POOL_SIZE)
+//   - WithSources: add configuration layers and customize their precedence
+//   - WithMergeOptions: list strategies, null deletes and conflict policy
+//     for merging the layers
+//   - WithSecretResolver: add, replace or disable a ${scheme:...} resolver
+//   - WithFileSecrets: enable ${file:...} references to files under a directory
+//   - WithCommandSecrets: enable ${cmd:...} references (runs programs; trusted
+//     configuration only)
+//   - WithProvenanceLog: log the origin of every field at startup
+//   - WithDecrypter: key for encrypted profiles (default: from the environment)
+//   - WithStrict: warn about or reject unknown keys, unknown prefixed env
+//     vars and type mismatches between layers
+//
+// Sources (lowest precedence first; the default order is profiles, extra
+// sources, environment):
+//
+//	p := config.NewProvider[AppConfig](config.WithSources(
+//	    source.Profiles,
+//	    source.Optional(source.JSON("app.json")),
+//	    source.HTTP(url, source.WithFallbackFile("/var/cache/app.yaml")),
+//	    source.Environment,
+//	    source.Flags(nil), // flags override environment variables
+//	))
+//
+// Secret references in profile and source values are resolved at load
+// time; the fields holding them are masked in logs like `safe` fields. The
+// file and cmd schemes are opt-in (WithFileSecrets, WithCommandSecrets):
+//
+//	database:
+//	  password: ${env:DB_PASSWORD}
+//	  token: ${file:/run/secrets/db}
+//	  dsn: postgres://app:${cmd:pass show db}@db/app
+//
+// Encrypted profiles: "production.yaml.enc" files (config/crypt) are
+// decrypted with the WithDecrypter key or the CONFIG_KEY / age identity
+// environment variables; their values are masked in logs:
+//
+//	config-crypt encrypt CONFIGS/production.yaml   # cmd/config-crypt
+//
+// Provenance: Explain reports where each field came from (profile
+// file:line, environment variable, `default` tag, source or update), with
+// secrets masked:
+//
+//	fmt.Println(p.Explain())
+//	// Database.Host = db.internal (profile CONFIGS/production.yml:3)
+//	// Port = 8080 (default APP_PORT)
+//
+// Strict mode: WithStrict(StrictFail) fails loading on profile or source
+// keys that match no field, on env vars with the WithEnvPrefix prefix that
+// match no field and on values the merge skips because their type differs
+// from the value they override (ErrUnknownKey, ErrUnknownEnv,
+// ErrTypeMismatch); StrictWarn only logs them:
+//
+//	// unknown key "databse.host" (profile CONFIGS/production.yml:3)
+//
+// Export: Export[T] loads the configuration like a Provider, writes it as
+// YAML or JSON with secrets masked and validates it, for CI checks of every
+// scope (see cmd/example-config); Provider.Export writes the current one:
+//
+//	err := config.Export[AppConfig](os.Stdout, config.FormatYAML,
+//	    config.WithScope("production"))
+//
+// DiffScopes[T] compares two scopes (their profiles and optional .env
+// files) key by key, e.g. before promoting staging settings to production;
+// `safe` values are masked:
+//
+//	diff, err := config.DiffScopes[AppConfig](
+//	    config.ScopeTarget{Scope: "staging"},
+//	    config.ScopeTarget{Scope: "production", EnvFile: "production.env"},
+//	    config.WithProfilesPath("CONFIGS"))
+//	fmt.Print(diff) // ~ database.host: db.staging -> db.production
+//
+// WriteEnvTemplate[T] writes a .env template of the environment variables of
+// T, named like the provider reads them; required ones (`env:"NAME,required"`)
+// are reported together by GetConfiguration and Load when missing.
 //
 // Sub-packages:
 //   - config/environment: env-var parsing via struct tags
 //   - config/profile: YAML profile loading and merging
 //   - config/merger: recursive deep-merge of map[string]any
+//   - config/source: JSON, TOML, YAML, flag, .env and in-memory sources
+//   - config/schema: JSON Schema and Markdown reference generation
+//   - config/crypt: AES-GCM and age encryption of profiles
+//   - config/flags: feature flags declared in the profiles
 //   - config/validation: struct validation via Validator interface
 package config
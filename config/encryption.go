@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/guionardo/go/config/crypt"
+)
+
+// WithDecrypter sets the key used to read encrypted profiles
+// ("production.yaml.enc"), e.g. a crypt.AESKey or crypt.AgeIdentities.
+// Without it the keys are read from the environment on every load (see
+// crypt.KeyringFromEnv).
+//
+// The values read from encrypted profiles are masked in logs, diffs and
+// provenance like `safe` fields.
+func WithDecrypter(decrypter crypt.Decrypter) providerOption {
+	return func(p *provider) {
+		p.decrypter = decrypter
+	}
+}
+
+// getDecrypter returns the configured decrypter or the environment keyring.
+func (p *provider) getDecrypter() crypt.Decrypter {
+	if p.decrypter != nil {
+		return p.decrypter
+	}
+
+	keyring, err := crypt.KeyringFromEnv()
+	if err != nil {
+		p.log().Warn("error reading profile decryption keys", "error", err)
+	}
+
+	return keyring
+}
+
+// encryptedPaths returns the yaml paths of the values of an encrypted
+// profile layer.
+func (l mapLayer) encryptedPaths() [][]string {
+	if !l.encrypted {
+		return nil
+	}
+
+	var paths [][]string
+
+	walkMapLeaves(l.values, nil, func(path []string) {
+		paths = append(paths, path)
+	})
+
+	return paths
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/crypt"
+)
+
+func TestEncryptedProfiles(t *testing.T) {
+	key, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	ciphertext, err := key.Encrypt([]byte("password: s3cr3t\n"))
+	require.NoError(t, err)
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"), []byte("name: app\n"), 0o600))
+	require.NoError(t, os.WriteFile(path.Join(tmp, "production.yml.enc"), ciphertext, 0o600))
+
+	newProvider := func(options ...providerOption) *Provider[exportConfig] {
+		return NewProvider[exportConfig](append([]providerOption{
+			WithProfilesPath(tmp), WithDefaultScope("default"), WithScope("production"),
+		}, options...)...)
+	}
+
+	t.Run("with_decrypter", func(t *testing.T) {
+		provider := newProvider(WithDecrypter(key))
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.Password)
+
+		var out bytes.Buffer
+		require.NoError(t, provider.Export(&out, FormatYAML))
+		assert.Contains(t, out.String(), "password: '********'\n")
+		assert.Equal(t, maskedValue, provider.Explain()["Password"].Value)
+		assert.Equal(t, "app", provider.Explain()["Name"].Value)
+	})
+
+	t.Run("key_from_env", func(t *testing.T) {
+		t.Setenv(crypt.EnvKey, key.String())
+
+		cfg, err := newProvider().GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.Password)
+	})
+
+	t.Run("missing_key", func(t *testing.T) {
+		t.Setenv(crypt.EnvKey, "")
+
+		var out bytes.Buffer
+		err := Export[exportConfig](&out, FormatYAML,
+			WithProfilesPath(tmp), WithDefaultScope("default"), WithScope("production"))
+		require.ErrorIs(t, err, crypt.ErrNoKey)
+	})
+}
@@ -0,0 +1,224 @@
+package environment
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+const (
+	// IndexPlaceholder stands for the index of a slice element in the
+	// Field and Name of repeated Variables: "Replicas.{N}.Host",
+	// "REPLICAS_{N}_HOST".
+	IndexPlaceholder = "{N}"
+
+	// KeyPlaceholder stands for the key of a map entry in the Field and
+	// Name of repeated Variables: "Upstreams.{KEY}.URL", "UPSTREAMS_{KEY}_URL".
+	KeyPlaceholder = "{KEY}"
+)
+
+// elemStruct returns the struct type of the elements of a slice or a
+// string-keyed map of structs (or pointers to structs), or nil for any
+// other type.
+func elemStruct(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Slice:
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct || structfield.IsScalarStruct(elem) || elem.NumField() == 0 {
+		return nil
+	}
+
+	return elem
+}
+
+// placeholder returns the placeholder of the elements of a slice or map.
+func placeholder(t reflect.Type) string {
+	if t.Kind() == reflect.Map {
+		return KeyPlaceholder
+	}
+
+	return IndexPlaceholder
+}
+
+// parseCollection parses the elements of the slice or map of structs v,
+// found at path, reading variables under prefix followed by the index or
+// key: REPLICAS_0_HOST, UPSTREAMS_BILLING_URL.
+func parseCollection(v reflect.Value, path, prefix string, o options, missing *[]Variable) error {
+	elem := elemStruct(v.Type())
+
+	var leaves []Variable
+
+	walkVariables(elem, "", "", o, &leaves)
+
+	if v.Kind() == reflect.Map {
+		return parseMap(v, elem, path, prefix, leaves, o, missing)
+	}
+
+	return parseSlice(v, elem, path, prefix, leaves, o, missing)
+}
+
+// parseSlice parses the current elements of v and appends one for each
+// following index with a variable set, until the first index without any.
+func parseSlice(v reflect.Value, elem reflect.Type, path, prefix string, leaves []Variable, o options,
+	missing *[]Variable,
+) (err error) {
+	for i := 0; ; i++ {
+		elemPrefix := prefix + strconv.Itoa(i) + "_"
+
+		if i >= v.Len() {
+			if !anySet(elemPrefix, leaves, o.lookup) {
+				return err
+			}
+
+			v.Set(reflect.Append(v, reflect.New(v.Type().Elem()).Elem()))
+		}
+
+		item := v.Index(i)
+		if item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				item.Set(reflect.New(elem))
+			}
+
+			item = item.Elem()
+		}
+
+		err = errors.Join(err, parseStruct(item, joinPath(path, strconv.Itoa(i)), elemPrefix, o, missing))
+	}
+}
+
+// parseMap parses the current entries of v and adds one for each key found
+// in the variable names.
+func parseMap(v reflect.Value, elem reflect.Type, path, prefix string, leaves []Variable, o options,
+	missing *[]Variable,
+) (err error) {
+	keys := mapKeys(v, prefix, leaves, o.names())
+	if len(keys) > 0 && v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	isPointer := v.Type().Elem().Kind() == reflect.Pointer
+
+	for _, key := range keys {
+		keyValue := reflect.ValueOf(key).Convert(v.Type().Key())
+
+		item := reflect.New(elem)
+		if current := v.MapIndex(keyValue); current.IsValid() {
+			switch {
+			case !isPointer:
+				item.Elem().Set(current)
+			case !current.IsNil():
+				item = current
+			}
+		}
+
+		err = errors.Join(err, parseStruct(item.Elem(), joinPath(path, key), prefix+keyEnvName(key)+"_", o, missing))
+
+		if isPointer {
+			v.SetMapIndex(keyValue, item)
+		} else {
+			v.SetMapIndex(keyValue, item.Elem())
+		}
+	}
+
+	return err
+}
+
+// mapKeys returns the keys of v, sorted, followed by the keys of the
+// variable names that start with prefix and end with an element variable
+// (the longest one wins: UPSTREAMS_BILLING_API_URL is key "billing" if the
+// element has an API_URL variable). New keys are lower-cased.
+func mapKeys(v reflect.Value, prefix string, leaves []Variable, names []string) []string {
+	var (
+		keys     []string
+		segments = map[string]bool{}
+		patterns []string
+	)
+
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		segments[keyEnvName(key)] = true
+	}
+
+	for _, leaf := range leaves {
+		if leaf.Name != "" {
+			patterns = append(patterns, namePattern(leaf.Name))
+		}
+	}
+
+	if len(patterns) == 0 {
+		return keys
+	}
+
+	re := regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "(.+?)_(?:" + strings.Join(patterns, "|") + ")$")
+
+	for _, name := range names {
+		match := re.FindStringSubmatch(name)
+		if match == nil || segments[match[1]] {
+			continue
+		}
+
+		segments[match[1]] = true
+		keys = append(keys, strings.ToLower(match[1]))
+	}
+
+	return keys
+}
+
+// anySet reports whether any element variable under prefix is set.
+func anySet(prefix string, leaves []Variable, lookup func(string) (string, bool)) bool {
+	for _, leaf := range leaves {
+		if leaf.Name == "" || leaf.Repeated() {
+			continue
+		}
+
+		if value, _ := lookup(prefix + leaf.Name); value != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keyEnvName returns the variable name segment of a map key: upper case,
+// with characters other than letters and digits replaced by "_".
+func keyEnvName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, key)
+}
+
+// namePattern returns the regular expression of a variable name, with its
+// placeholders matching any index or key.
+func namePattern(name string) string {
+	return strings.NewReplacer(
+		regexp.QuoteMeta(IndexPlaceholder), "[0-9]+",
+		regexp.QuoteMeta(KeyPlaceholder), ".+",
+	).Replace(regexp.QuoteMeta(name))
+}
@@ -0,0 +1,136 @@
+package environment_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+)
+
+type (
+	Replica struct {
+		Host string
+		Port int `default:"5432"`
+	}
+
+	Upstream struct {
+		URL     string `env:",required"`
+		APIURL  string `env:"API_URL"`
+		Retries int
+	}
+
+	CollectionConfig struct {
+		Replicas  []Replica
+		Upstreams map[string]*Upstream
+		Shards    []Replica `envPrefix:"SHARD_"`
+		Untagged  []Replica `env:"-"`
+		Store     any
+		Name      any
+	}
+)
+
+func TestParse_Collections(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"DB_REPLICAS_0_HOST":                 "primary",
+		"DB_REPLICAS_1_HOST":                 "replica",
+		"DB_REPLICAS_1_PORT":                 "6432",
+		"DB_REPLICAS_3_HOST":                 "not consecutive",
+		"DB_UPSTREAMS_BILLING_URL":           "http://billing",
+		"DB_UPSTREAMS_AUTH_SERVICE_URL":      "http://auth",
+		"DB_UPSTREAMS_BILLING_API_URL":       "http://billing/api",
+		"DB_UPSTREAMS_SEARCH_ENGINE_RETRIES": "3",
+		"DB_UPSTREAMS_SEARCH_ENGINE_URL":     "http://search",
+		"DB_SHARD_0_HOST":                    "shard",
+		"DB_STORE_HOST":                      "store",
+		"DB_NAME":                            "app",
+	}
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+
+	options := []environment.Option{
+		environment.WithPrefix("DB_"), environment.WithAutoNaming(), mapLookup(env), environment.WithEnviron(names),
+	}
+
+	t.Run("indexed_and_keyed", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := CollectionConfig{
+			Replicas:  []Replica{{Host: "from profile", Port: 1}},
+			Upstreams: map[string]*Upstream{"search-engine": {URL: "http://old", APIURL: "http://search/api"}},
+			Store:     &Replica{},
+		}
+
+		var assignments []string
+
+		err := environment.Parse(&cfg, append(options, environment.WithOnSet(func(a environment.Assignment) {
+			assignments = append(assignments, a.Field+"="+a.Env)
+		}))...)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Replica{{Host: "primary", Port: 5432}, {Host: "replica", Port: 6432}}, cfg.Replicas)
+		assert.Equal(t, []Replica{{Host: "shard", Port: 5432}}, cfg.Shards)
+		assert.Equal(t, map[string]*Upstream{
+			"billing":       {URL: "http://billing", APIURL: "http://billing/api"},
+			"auth_service":  {URL: "http://auth"},
+			"search-engine": {URL: "http://search", APIURL: "http://search/api", Retries: 3},
+		}, cfg.Upstreams)
+		assert.Equal(t, &Replica{Host: "store", Port: 5432}, cfg.Store)
+		assert.Equal(t, "app", cfg.Name)
+		assert.Contains(t, assignments, "Replicas.1.Port=DB_REPLICAS_1_PORT")
+		assert.Contains(t, assignments, "Upstreams.billing.URL=DB_UPSTREAMS_BILLING_URL")
+	})
+
+	t.Run("missing_required_element_variable", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := CollectionConfig{Upstreams: map[string]*Upstream{"cache": nil}}
+
+		err := environment.Parse(&cfg, options...)
+		require.ErrorIs(t, err, environment.ErrMissingEnv)
+		assert.Contains(t, err.Error(), "DB_UPSTREAMS_CACHE_URL (Upstreams.cache.URL)")
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		t.Parallel()
+
+		variables := environment.Variables(reflect.TypeFor[CollectionConfig](), options...)
+
+		byField := map[string]environment.Variable{}
+		for _, variable := range variables {
+			byField[variable.Field] = variable
+		}
+
+		replicaHost := byField["Replicas.{N}.Host"]
+		assert.Equal(t, "DB_REPLICAS_{N}_HOST", replicaHost.Name)
+		assert.True(t, replicaHost.Repeated())
+		assert.True(t, replicaHost.Match("DB_REPLICAS_12_HOST"))
+		assert.False(t, replicaHost.Match("DB_REPLICAS_X_HOST"))
+
+		upstreamURL := byField["Upstreams.{KEY}.URL"]
+		assert.Equal(t, "DB_UPSTREAMS_{KEY}_URL", upstreamURL.Name)
+		assert.True(t, upstreamURL.Required)
+		assert.True(t, upstreamURL.Match("DB_UPSTREAMS_BILLING_URL"))
+
+		assert.Equal(t, "DB_SHARD_{N}_PORT", byField["Shards.{N}.Port"].Name)
+		assert.False(t, byField["Store"].Repeated())
+		assert.True(t, byField["Store"].Match("DB_STORE"))
+		assert.NotContains(t, byField, "Untagged.{N}.Host")
+	})
+
+	t.Run("template", func(t *testing.T) {
+		t.Parallel()
+
+		var buf strings.Builder
+		require.NoError(t, environment.WriteTemplate(&buf, reflect.TypeFor[CollectionConfig](), options...))
+		assert.Contains(t, buf.String(), "# Replicas.{N}.Port (default 5432)\n# DB_REPLICAS_{N}_PORT=5432\n")
+		assert.Contains(t, buf.String(), "# Upstreams.{KEY}.URL (required)\n# DB_UPSTREAMS_{KEY}_URL=\n")
+	})
+}
@@ -2,8 +2,20 @@
 // from environment variables into struct fields.
 //
 // Uses env and default struct tags. Supports string, int, uint, bool,
-// float, time.Duration, and nested struct types. Matching is
-// case-insensitive.
+// float, time.Duration, time.Time (any layout known to time_tools.Parse),
+// url.URL, net.IP, encoding.TextUnmarshaler, pointers, slices, maps,
+// sets (map[T]struct{}, e.g. set.Set[T]) and nested struct types.
+// Matching is case-insensitive.
+//
+// Slices, sets and maps are split by `envSeparator` (default ","); map
+// entries are "key=value" pairs split by `envKeyValSeparator` (default "="):
+//
+//	type Config struct {
+//	    Hosts   []string          `env:"APP_HOSTS"`                         // a,b,c
+//	    Ports   []int             `env:"APP_PORTS" envSeparator:";"`        // 80;443
+//	    Labels  map[string]string `env:"APP_LABELS"`                        // env=prod,team=core
+//	    Timeout time.Duration     `env:"APP_TIMEOUT" default:"30s"`
+//	}
 //
 // Example:
 //
@@ -12,7 +24,57 @@
 //	    Host string `env:"APP_HOST" default:"localhost"`
 //	}
 //
+// Naming: Parse accepts options to prefix every variable name and to derive
+// names for untagged fields from their path. Nested structs add their
+// `envPrefix` tag (or, with auto naming, their field name) to the prefix,
+// and `env:"-"` skips a field or a whole nested struct:
+//
+//	type Config struct {
+//	    Database DB                        // MYAPP_DATABASE_POOL_SIZE
+//	    Replica  DB `envPrefix:"RO_"`      // MYAPP_RO_POOL_SIZE
+//	    Internal DB `env:"-"`              // never read from the environment
+//	}
+//	type DB struct {
+//	    Pool struct{ Size int }
+//	}
+//
+//	err := environment.Parse(&cfg, environment.WithPrefix("MYAPP_"), environment.WithAutoNaming())
+//
+// Collections: slices and string-keyed maps of structs read their elements
+// from indexed and keyed variables under the `envPrefix` tag, `env` tag name
+// or (auto naming) field name of the field. Indexes are consecutive from 0
+// and extend the current elements; map keys are the current keys plus the
+// keys found in the variable names (lower-cased, see WithEnviron):
+//
+//	type Config struct {
+//	    Replicas  []Replica           // MYAPP_REPLICAS_0_HOST, MYAPP_REPLICAS_1_HOST
+//	    Upstreams map[string]Upstream // MYAPP_UPSTREAMS_BILLING_URL → Upstreams["billing"].URL
+//	}
+//
+// Variables lists their fields once, with IndexPlaceholder or
+// KeyPlaceholder ("MYAPP_REPLICAS_{N}_HOST"); Variable.Match matches actual
+// names. An interface field holding a pointer to a struct is walked like a
+// nested struct; an empty interface field is set to the string value.
+//
+// WithLookup replaces os.LookupEnv, e.g. to fall back to variables read from a .env file.
+// WithLogger receives the applied and missing variables (names only, never
+// values); parse errors mask the value of `safe` fields.
+//
+// Required: `env:"NAME,required"` (or `env:",required"` with auto naming)
+// marks a variable that must be set, even when it has a default. Parse
+// checks every field and reports all missing required variables at once in
+// a MissingError (matching ErrMissingEnv), joined with the other errors:
+//
+//	missing required environment variables: APP_DB_URL (DB.URL), APP_TOKEN (Token)
+//
+// WriteTemplate writes a ready-to-fill .env template of a struct type, with
+// the field path, `description` tag, required marker and default of every
+// variable.
+//
 // Functions:
 //   - GetEnv: get env var with optional default
-//   - ParseEnvironment: populate a struct from env vars using struct tags
+//   - Parse: populate a struct from env vars using struct tags and options
+//   - ParseEnvironment: Parse without options (kept for compatibility)
+//   - Variables: list the variables of a struct type
+//   - WriteTemplate: write a .env template of a struct type
 package environment
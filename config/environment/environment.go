@@ -7,9 +7,13 @@ import (
 	"os"
 	"reflect"
 	"runtime/debug"
-	"strconv"
+
+	"github.com/guionardo/go/config/internal/structfield"
 )
 
+// maskedValue replaces the value of `safe` fields in errors.
+const maskedValue = "********"
+
 // GetEnv returns the value of the environment variable, or a default if not set.
 func GetEnv(env string, defaultValue ...string) string {
 	if env == "" {
@@ -30,10 +34,30 @@ func GetEnv(env string, defaultValue ...string) string {
 // ParseEnvironment parses the environment variables into a struct
 // It returns an error if the environment variables are invalid
 // The argument must be a pointer to a struct
-func ParseEnvironment(s any, parentType reflect.Type) (err error) {
+//
+// It is equivalent to Parse(s) and is kept for compatibility; parentType is ignored.
+func ParseEnvironment(s any, parentType reflect.Type) error {
+	return Parse(s)
+}
+
+// Parse parses the environment variables into a struct, configured by opts.
+// It returns an error if the environment variables are invalid
+// The argument must be a pointer to a struct
+//
+// Slices and string-keyed maps of structs read their elements from indexed
+// and keyed variables under the prefix of the field (its `envPrefix` tag,
+// `env` tag name or, with auto naming, field name): REPLICAS_0_HOST, REPLICAS_1_HOST (consecutive indexes from 0, appended
+// after the current elements) and UPSTREAMS_BILLING_URL (keys of the
+// current entries and keys found in the variable names, see WithEnviron).
+//
+// Every unset variable marked required (`env:"NAME,required"`) is listed
+// in a single *MissingError, joined with the other errors.
+func Parse(s any, opts ...Option) (err error) {
+	o := newOptions(opts)
+
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
-			slog.Error("panic in ParseEnvironment", "panic", panicErr, "stack", string(debug.Stack()))
+			o.logger.Error("panic in ParseEnvironment", "panic", panicErr, "stack", string(debug.Stack()))
 			err = fmt.Errorf("panic: %v", panicErr)
 		}
 	}()
@@ -44,127 +68,166 @@ func ParseEnvironment(s any, parentType reflect.Type) (err error) {
 		return fmt.Errorf("expected struct, got %s", t.Kind())
 	}
 
+	var missing []Variable
+
+	err = parseStruct(reflect.ValueOf(s).Elem(), "", o.prefix, o, &missing)
+	if len(missing) > 0 {
+		err = errors.Join(&MissingError{Variables: missing}, err)
+	}
+
+	return err
+}
+
+// parseStruct parses the fields of the struct v, found at the dotted field
+// path parentPath, reading variables under prefix. Unset required
+// variables are appended to missing.
+func parseStruct(v reflect.Value, parentPath, prefix string, o options, missing *[]Variable) (err error) {
+	t := v.Type()
+
 	var (
 		envName     string
 		envValue    string
 		envFound    bool
-		setEnvs     = map[string]string{} // field:env name
-		missingEnvs = map[string]string{} // field:env name
+		missingEnvs []string
 	)
 
-	for i := 0; i < t.Elem().NumField(); i++ {
-		field := t.Elem().Field(i)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("env") == skipTag {
+			continue
+		}
 
-		if field.Type.Kind() == reflect.Struct {
-			fieldValue := reflect.ValueOf(s).Elem().Field(i)
-			if parseErr := ParseEnvironment(fieldValue.Addr().Interface(), t); parseErr != nil {
+		fieldValue := v.Field(i)
+
+		if field.Type.Kind() == reflect.Struct && !structfield.IsScalarStruct(field.Type) {
+			if parseErr := parseStruct(fieldValue, joinPath(parentPath, field.Name),
+				prefix+o.structPrefix(field), o, missing); parseErr != nil {
 				err = errors.Join(err, parseErr)
 			}
 
 			continue
 		}
 
-		envValue, envName, envFound = getFieldEnvValue(field)
-		if !envFound {
-			missingEnvs[field.Name] = envName
+		if nested, ok := interfaceStruct(fieldValue); ok {
+			if parseErr := parseStruct(nested, joinPath(parentPath, field.Name),
+				prefix+o.structPrefix(field), o, missing); parseErr != nil {
+				err = errors.Join(err, parseErr)
+			}
+
+			continue
 		}
 
-		if envValue == "" {
+		if elemStruct(field.Type) != nil && o.collectionPrefix(field) != "" {
+			if parseErr := parseCollection(fieldValue, joinPath(parentPath, field.Name),
+				prefix+o.collectionPrefix(field), o, missing); parseErr != nil {
+				err = errors.Join(err, parseErr)
+			}
+
 			continue
 		}
 
-		fieldValue := reflect.ValueOf(s).Elem().Field(i)
+		envName = o.envName(field, prefix)
+		envValue, envFound = getFieldEnvValue(field, envName, o.lookup)
+		if !envFound && envName != "" {
+			missingEnvs = append(missingEnvs, envName)
+
+			if _, required := envTag(field); required {
+				*missing = append(*missing, newVariable(field, joinPath(parentPath, field.Name), envName))
+			}
+		}
+
+		if envValue == "" {
+			continue
+		}
 
 		if fieldValue.CanSet() {
-			if setErr := setField(field, fieldValue, envValue); setErr != nil {
+			fieldPath := joinPath(parentPath, field.Name)
+			if setErr := setField(field, fieldValue, envValue, o); setErr != nil {
 				err = errors.Join(err, setErr)
 			} else {
-				setEnvs[field.Name] = envName
+				o.logger.Debug("environment variable applied",
+					slog.String("field", fieldPath), slog.String("env", envName), slog.Bool("default", !envFound))
+				o.report(Assignment{Field: fieldPath, Env: envName, Default: !envFound})
 			}
 		}
 	}
-	// debug log
-	if len(setEnvs) > 0 {
-		logArgs := []any{slog.String("instance", t.String())}
-		for fieldName, envName := range setEnvs {
-			logArgs = append(logArgs, slog.String(fieldName, envName))
-		}
-
-		slog.Debug("config.SetEnv", logArgs...)
-	}
 
 	if len(missingEnvs) > 0 {
-		logArgs := []any{slog.String("instance", t.String())}
-		for fieldName, envName := range missingEnvs {
-			logArgs = append(logArgs, slog.String(fieldName, envName))
-		}
-
-		slog.Debug("config.SetEnv MISSING ENVS", logArgs...)
+		o.logger.Debug("environment variables not set",
+			slog.String("instance", t.String()), slog.Any("env", missingEnvs))
 	}
 
 	return err
 }
 
-// getFieldEnvValue returns the environment value for a field or the default value if not set
-func getFieldEnvValue(field reflect.StructField) (value string, envName string, found bool) {
-	if envName = field.Tag.Get("env"); envName != "" {
-		if envValue := os.Getenv(envName); envValue != "" {
-			return envValue, envName, true
+// getFieldEnvValue returns the value of the envName variable for a field or
+// the default value if not set (or if the field has no environment name).
+func getFieldEnvValue(
+	field reflect.StructField,
+	envName string,
+	lookup func(string) (string, bool),
+) (value string, found bool) {
+	if envName != "" {
+		if envValue, _ := lookup(envName); envValue != "" {
+			return envValue, true
 		}
 	}
 
-	return field.Tag.Get("default"), envName, false
+	return field.Tag.Get("default"), false
 }
 
-func setField(field reflect.StructField, fieldValue reflect.Value, envValue string) (err error) {
+// setField parses envValue into the field. The value of a `safe` field is
+// masked in the returned error.
+func setField(field reflect.StructField, fieldValue reflect.Value, envValue string, o options) (err error) {
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
-			slog.Error("panic in setField", "field", field.Name, "panic", panicErr, "stack", string(debug.Stack()))
+			o.logger.Error("panic in setField", "field", field.Name, "panic", panicErr, "stack", string(debug.Stack()))
 			err = fmt.Errorf("panic setting field %s: %v", field.Name, panicErr)
 		}
 	}()
 
-	switch field.Type.Kind() {
-	case reflect.String:
-		fieldValue.SetString(envValue)
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		var intValue int64
-
-		if intValue, err = strconv.ParseInt(envValue, 10, 64); err == nil {
-			fieldValue.SetInt(intValue)
+	if field.Type.Kind() == reflect.Struct && !structfield.IsScalarStruct(field.Type) {
+		if err = parseStruct(fieldValue, "", "", newOptions([]Option{WithLogger(o.logger)}), &[]Variable{}); err != nil {
+			return fmt.Errorf("invalid struct value for field %s: %w", field.Name, err)
 		}
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		var uintValue uint64
+		return nil
+	}
 
-		if uintValue, err = strconv.ParseUint(envValue, 10, 64); err == nil {
-			fieldValue.SetUint(uintValue)
+	if err = setValue(fieldValue, envValue, getValueOptions(field)); err != nil {
+		if _, safe := field.Tag.Lookup("safe"); safe {
+			envValue = maskedValue
+			err = errors.New("invalid value")
 		}
 
-	case reflect.Bool:
-		var boolValue bool
-
-		if boolValue, err = strconv.ParseBool(envValue); err == nil {
-			fieldValue.SetBool(boolValue)
-		}
+		err = fmt.Errorf("invalid field value '%s' (%s) for field %s: %w", envValue,
+			field.Type.Kind().String(), field.Name, err)
+	}
 
-	case reflect.Float64, reflect.Float32:
-		var floatValue float64
+	return err
+}
 
-		if floatValue, err = strconv.ParseFloat(envValue, 64); err == nil {
-			fieldValue.SetFloat(floatValue)
-		}
+// interfaceStruct returns the struct pointed to by the interface value v,
+// which is walked like a nested struct when set to a pointer to a
+// configuration struct.
+func interfaceStruct(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return reflect.Value{}, false
+	}
 
-	case reflect.Struct:
-		if err = ParseEnvironment(fieldValue.Addr().Interface(), nil); err != nil {
-			return fmt.Errorf("invalid struct value for field %s: %w", field.Name, err)
-		}
+	elem := v.Elem()
+	if elem.Kind() != reflect.Pointer || elem.IsNil() ||
+		elem.Elem().Kind() != reflect.Struct || structfield.IsScalarStruct(elem.Elem().Type()) {
+		return reflect.Value{}, false
 	}
 
-	if err != nil {
-		err = fmt.Errorf("invalid field value '%s' (%s) for field %s: %w", envValue,
-			field.Type.Kind().String(), field.Name, err)
+	return elem.Elem(), true
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
 	}
 
-	return err
+	return parent + "." + name
 }
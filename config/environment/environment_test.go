@@ -1,6 +1,7 @@
 package environment_test
 
 import (
+	"bytes"
 	"log/slog"
 	"runtime"
 	"testing"
@@ -120,3 +121,33 @@ func TestParseEnvironment(t *testing.T) { //nolint:paralleltest
 	assert.Equal(t, "sub_name2", ts.SubStruct.Name)
 	assert.Equal(t, 20, ts.SubStruct.Age)
 }
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Port     int    `env:"PORT"`
+		Password int    `env:"PASSWORD" safe:"true"`
+		Host     string `env:"HOST"`
+	}
+
+	var (
+		buf    bytes.Buffer
+		logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		env    = map[string]string{"PORT": "8080", "PASSWORD": "hunter2"}
+	)
+
+	var cfg config
+	err := environment.Parse(&cfg, environment.WithLogger(logger), environment.WithLookup(func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.Contains(t, err.Error(), "Password")
+
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Contains(t, buf.String(), `msg="environment variable applied" field=Port env=PORT default=false`)
+	assert.Contains(t, buf.String(), `msg="environment variables not set"`)
+	assert.NotContains(t, buf.String(), "8080")
+}
@@ -0,0 +1,228 @@
+package environment
+
+import (
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+type (
+	// Option configures Parse.
+	Option func(*options)
+
+	// Logger receives the parsing events; *slog.Logger and config.Logger
+	// implementations satisfy it.
+	Logger interface {
+		Debug(msg string, args ...any)
+		Info(msg string, args ...any)
+		Warn(msg string, args ...any)
+		Error(msg string, args ...any)
+	}
+
+	// Assignment describes a field set by Parse.
+	Assignment struct {
+		// Field is the dotted Go field path, e.g. "Database.Pool.Size".
+		Field string
+		// Env is the variable name of the field ("" if it has none).
+		Env string
+		// Default reports that the variable was not set and the value
+		// came from the `default` tag.
+		Default bool
+	}
+
+	options struct {
+		prefix     string
+		autoNaming bool
+		lookup     func(name string) (string, bool)
+		environ    []string
+		onSet      func(Assignment)
+		logger     Logger
+	}
+)
+
+const (
+	// skipTag opts a field (or a whole nested struct) out of environment parsing: `env:"-"`.
+	skipTag = "-"
+
+	// requiredOption marks a variable that must be set: `env:"DATABASE_URL,required"`
+	// (`env:",required"` with auto naming).
+	requiredOption = "required"
+
+	tagOptionSeparator = ","
+)
+
+// WithPrefix prepends prefix to every environment variable name, explicit
+// `env` tags included: with WithPrefix("MYAPP_"), `env:"PORT"` reads MYAPP_PORT.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithAutoNaming derives the variable name of fields without an `env` tag
+// from their path, in upper snake case: Database.Pool.Size reads
+// DATABASE_POOL_SIZE. Explicit `env` tags still win.
+func WithAutoNaming() Option {
+	return func(o *options) {
+		o.autoNaming = true
+	}
+}
+
+// WithLookup replaces os.LookupEnv as the source of variable values,
+// e.g. to layer a .env file below the process environment.
+func WithLookup(lookup func(name string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookup = lookup
+	}
+}
+
+// WithEnviron sets the variable names searched for the keys of maps of
+// structs (default: the names in os.Environ), e.g. the names visible
+// through WithLookup.
+func WithEnviron(names []string) Option {
+	return func(o *options) {
+		o.environ = names
+	}
+}
+
+// WithOnSet calls fn for every field set by Parse, from a variable or from
+// its `default` tag, e.g. to record where configuration values came from.
+func WithOnSet(fn func(Assignment)) Option {
+	return func(o *options) {
+		o.onSet = fn
+	}
+}
+
+// WithLogger sets the logger of the parsing events (defaults to
+// slog.Default()). Variable values are never logged.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func (o options) report(a Assignment) {
+	if o.onSet != nil {
+		o.onSet(a)
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.lookup == nil {
+		o.lookup = os.LookupEnv
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	return o
+}
+
+// names returns the variable names searched for map keys.
+func (o options) names() []string {
+	if o.environ != nil {
+		return o.environ
+	}
+
+	names := make([]string, 0, len(os.Environ()))
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// envName returns the variable name for a leaf field under prefix,
+// or "" when the field has no name (no tag and no auto naming).
+func (o options) envName(field reflect.StructField, prefix string) string {
+	if name, _ := envTag(field); name != "" {
+		return prefix + name
+	}
+
+	if o.autoNaming {
+		return prefix + toEnvName(field.Name)
+	}
+
+	return ""
+}
+
+// envTag returns the variable name of the `env` tag and whether it has the
+// required option.
+func envTag(field reflect.StructField) (name string, required bool) {
+	name, options, _ := strings.Cut(field.Tag.Get("env"), tagOptionSeparator)
+
+	for option := range strings.SplitSeq(options, tagOptionSeparator) {
+		if strings.TrimSpace(option) == requiredOption {
+			required = true
+		}
+	}
+
+	return strings.TrimSpace(name), required
+}
+
+// structPrefix returns the prefix segment a nested struct field adds for its
+// own fields: the `envPrefix` tag if set, otherwise the field name in auto
+// naming mode (embedded structs add nothing).
+func (o options) structPrefix(field reflect.StructField) string {
+	if prefix, ok := field.Tag.Lookup("envPrefix"); ok {
+		return prefix
+	}
+
+	if o.autoNaming && !field.Anonymous {
+		return toEnvName(field.Name) + "_"
+	}
+
+	return ""
+}
+
+// collectionPrefix returns the prefix segment a slice or map of structs
+// adds before the index or key of its elements: the `envPrefix` tag if
+// set, otherwise the `env` tag name or, in auto naming mode, the field name.
+// Without any, "" is returned and the elements are not read.
+func (o options) collectionPrefix(field reflect.StructField) string {
+	if prefix, ok := field.Tag.Lookup("envPrefix"); ok {
+		return prefix
+	}
+
+	if name, _ := envTag(field); name != "" {
+		return name + "_"
+	}
+
+	if o.autoNaming {
+		return toEnvName(field.Name) + "_"
+	}
+
+	return ""
+}
+
+// toEnvName converts a Go identifier to upper snake case:
+// PoolSize → POOL_SIZE, HTTPServer → HTTP_SERVER, MaxConns2 → MAX_CONNS2.
+func toEnvName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
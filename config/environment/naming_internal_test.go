@@ -0,0 +1,24 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToEnvName(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]string{
+		"Size":       "SIZE",
+		"PoolSize":   "POOL_SIZE",
+		"HTTPServer": "HTTP_SERVER",
+		"ServerURL":  "SERVER_URL",
+		"ID":         "ID",
+		"MaxConns2":  "MAX_CONNS2",
+		"V2Api":      "V2_API",
+		"lowerStart": "LOWER_START",
+	} {
+		assert.Equal(t, want, toEnvName(name), name)
+	}
+}
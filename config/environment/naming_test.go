@@ -0,0 +1,148 @@
+package environment_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+)
+
+type (
+	PoolConfig struct {
+		Size    int
+		MaxIdle int `env:"IDLE"`
+	}
+
+	DatabaseConfig struct {
+		Host string
+		Pool PoolConfig
+	}
+
+	EmbeddedConfig struct {
+		Region string
+	}
+
+	NamedConfig struct {
+		EmbeddedConfig
+
+		Database DatabaseConfig
+		Replica  DatabaseConfig `envPrefix:"RO_"`
+		HTTPPort int            `default:"8080"`
+		Secret   string         `env:"-"`
+		Ignored  DatabaseConfig `env:"-"`
+	}
+)
+
+func TestParseAutoNaming(t *testing.T) { //nolint:paralleltest
+	t.Setenv("DATABASE_HOST", "db")
+	t.Setenv("DATABASE_POOL_SIZE", "10")
+	t.Setenv("DATABASE_POOL_IDLE", "2")
+	t.Setenv("RO_HOST", "replica")
+	t.Setenv("RO_POOL_SIZE", "5")
+	t.Setenv("REGION", "sa-east-1")
+	t.Setenv("SECRET", "leaked")
+	t.Setenv("IGNORED_HOST", "ignored")
+
+	var cfg NamedConfig
+	require.NoError(t, environment.Parse(&cfg, environment.WithAutoNaming()))
+
+	assert.Equal(t, "db", cfg.Database.Host)
+	assert.Equal(t, 10, cfg.Database.Pool.Size)
+	assert.Equal(t, 2, cfg.Database.Pool.MaxIdle)
+	assert.Equal(t, "replica", cfg.Replica.Host)
+	assert.Equal(t, 5, cfg.Replica.Pool.Size)
+	assert.Equal(t, "sa-east-1", cfg.Region)
+	assert.Equal(t, 8080, cfg.HTTPPort)
+	assert.Empty(t, cfg.Secret)
+	assert.Empty(t, cfg.Ignored.Host)
+}
+
+func TestParsePrefix(t *testing.T) { //nolint:paralleltest
+	t.Run("auto_naming", func(t *testing.T) {
+		t.Setenv("MYAPP_DATABASE_POOL_SIZE", "20")
+		t.Setenv("MYAPP_HTTP_PORT", "9090")
+		t.Setenv("DATABASE_POOL_SIZE", "1")
+
+		var cfg NamedConfig
+		require.NoError(t, environment.Parse(&cfg, environment.WithPrefix("MYAPP_"), environment.WithAutoNaming()))
+
+		assert.Equal(t, 20, cfg.Database.Pool.Size)
+		assert.Equal(t, 9090, cfg.HTTPPort)
+	})
+
+	t.Run("explicit_tags", func(t *testing.T) {
+		t.Setenv("MYAPP_NAME", "prefixed")
+		t.Setenv("NAME", "plain")
+		t.Setenv("MYAPP_DATABASE_POOL_SIZE", "20")
+
+		var cfg TestStruct
+		require.NoError(t, environment.Parse(&cfg, environment.WithPrefix("MYAPP_")))
+
+		assert.Equal(t, "prefixed", cfg.SubStruct.Name)
+	})
+
+	t.Run("env_prefix_without_auto_naming", func(t *testing.T) {
+		t.Setenv("RO_IDLE", "3")
+		t.Setenv("IDLE", "1")
+
+		var cfg NamedConfig
+		require.NoError(t, environment.Parse(&cfg))
+
+		assert.Equal(t, 3, cfg.Replica.Pool.MaxIdle)
+		assert.Equal(t, 1, cfg.Database.Pool.MaxIdle)
+		assert.Zero(t, cfg.Database.Pool.Size, "untagged fields need auto naming")
+	})
+}
+
+func TestParseOnSet(t *testing.T) { //nolint:paralleltest
+	t.Setenv("DATABASE_HOST", "db")
+
+	var assignments []environment.Assignment
+
+	var cfg NamedConfig
+	require.NoError(t, environment.Parse(&cfg, environment.WithAutoNaming(),
+		environment.WithOnSet(func(a environment.Assignment) {
+			assignments = append(assignments, a)
+		})))
+
+	assert.ElementsMatch(t, []environment.Assignment{
+		{Field: "Database.Host", Env: "DATABASE_HOST"},
+		{Field: "HTTPPort", Env: "HTTP_PORT", Default: true},
+	}, assignments)
+}
+
+func TestVariables(t *testing.T) {
+	t.Parallel()
+
+	variables := environment.Variables(reflect.TypeFor[*NamedConfig](),
+		environment.WithPrefix("APP_"), environment.WithAutoNaming())
+
+	names := map[string]string{}
+	for _, v := range variables {
+		names[v.Field] = v.Name
+	}
+
+	assert.Equal(t, map[string]string{
+		"EmbeddedConfig.Region": "APP_REGION",
+		"Database.Host":         "APP_DATABASE_HOST",
+		"Database.Pool.Size":    "APP_DATABASE_POOL_SIZE",
+		"Database.Pool.MaxIdle": "APP_DATABASE_POOL_IDLE",
+		"Replica.Host":          "APP_RO_HOST",
+		"Replica.Pool.Size":     "APP_RO_POOL_SIZE",
+		"Replica.Pool.MaxIdle":  "APP_RO_POOL_IDLE",
+		"HTTPPort":              "APP_HTTP_PORT",
+	}, names)
+
+	last := variables[len(variables)-1]
+	assert.Equal(t, "HTTPPort", last.Field)
+	assert.Equal(t, "8080", last.Default)
+	assert.True(t, last.HasDefault)
+
+	assert.Empty(t, environment.Variables(reflect.TypeFor[string]()))
+
+	plain := environment.Variables(reflect.TypeFor[NamedConfig]())
+	assert.Empty(t, plain[0].Name, "untagged fields have no name without auto naming")
+}
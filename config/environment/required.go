@@ -0,0 +1,33 @@
+package environment
+
+import (
+	"errors"
+	"strings"
+)
+
+type (
+	// MissingError lists the required variables (`env:"NAME,required"`)
+	// that are not set, in field order.
+	MissingError struct {
+		Variables []Variable
+	}
+)
+
+// ErrMissingEnv matches a *MissingError with errors.Is.
+var ErrMissingEnv = errors.New("missing required environment variables")
+
+// Error lists the missing variables with their field paths:
+// "missing required environment variables: DATABASE_URL (Database.URL), API_KEY (APIKey)".
+func (e *MissingError) Error() string {
+	names := make([]string, len(e.Variables))
+	for i, variable := range e.Variables {
+		names[i] = variable.Name + " (" + variable.Field + ")"
+	}
+
+	return ErrMissingEnv.Error() + ": " + strings.Join(names, ", ")
+}
+
+// Is reports whether target is ErrMissingEnv.
+func (e *MissingError) Is(target error) bool {
+	return target == ErrMissingEnv
+}
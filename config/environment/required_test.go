@@ -0,0 +1,114 @@
+package environment_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+)
+
+type (
+	RequiredDatabase struct {
+		URL      string `env:"URL,required" safe:"true" description:"connection string"`
+		PoolSize int    `default:"4"`
+	}
+
+	RequiredConfig struct {
+		Name     string `env:",required"`
+		Port     int    `env:"PORT" default:"8080"`
+		Region   string `env:"REGION,required" default:"us"`
+		Debug    bool
+		Database RequiredDatabase
+	}
+)
+
+func mapLookup(env map[string]string) environment.Option {
+	return environment.WithLookup(func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+}
+
+func TestParse_Required(t *testing.T) {
+	t.Parallel()
+
+	options := []environment.Option{environment.WithPrefix("APP_"), environment.WithAutoNaming()}
+
+	t.Run("lists_every_missing_variable", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg RequiredConfig
+		err := environment.Parse(&cfg, append(options, mapLookup(map[string]string{"APP_PORT": "x"}))...)
+		require.ErrorIs(t, err, environment.ErrMissingEnv)
+
+		var missingErr *environment.MissingError
+		require.ErrorAs(t, err, &missingErr)
+
+		names := make([]string, len(missingErr.Variables))
+		for i, variable := range missingErr.Variables {
+			names[i] = variable.Name + " " + variable.Field
+		}
+
+		assert.Equal(t, []string{"APP_NAME Name", "APP_REGION Region", "APP_DATABASE_URL Database.URL"}, names)
+		assert.Contains(t, err.Error(),
+			"missing required environment variables: APP_NAME (Name), APP_REGION (Region), APP_DATABASE_URL (Database.URL)")
+		assert.Contains(t, err.Error(), "field Port", "other errors are joined")
+	})
+
+	t.Run("all_set", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg RequiredConfig
+		require.NoError(t, environment.Parse(&cfg, append(options, mapLookup(map[string]string{
+			"APP_NAME": "app", "APP_REGION": "eu", "APP_DATABASE_URL": "postgres://db",
+		}))...))
+		assert.Equal(t, "eu", cfg.Region)
+		assert.Equal(t, 8080, cfg.Port)
+	})
+
+	t.Run("variables_report_required", func(t *testing.T) {
+		t.Parallel()
+
+		variables := environment.Variables(reflect.TypeFor[RequiredConfig](), options...)
+		require.Len(t, variables, 6)
+		assert.True(t, variables[0].Required)
+		assert.Equal(t, "APP_NAME", variables[0].Name)
+		assert.False(t, variables[1].Required)
+	})
+}
+
+func TestWriteTemplate(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+	require.NoError(t, environment.WriteTemplate(&buf, reflect.TypeFor[RequiredConfig](),
+		environment.WithPrefix("APP_"), environment.WithAutoNaming()))
+
+	assert.Equal(t, `# Name (required)
+APP_NAME=
+
+# Port (default 8080)
+# APP_PORT=8080
+
+# Region (required, default us)
+APP_REGION=us
+
+# Debug
+APP_DEBUG=
+
+# Database.URL: connection string (required, secret)
+APP_DATABASE_URL=
+
+# Database.PoolSize (default 4)
+# APP_DATABASE_POOL_SIZE=4
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, environment.WriteTemplate(&buf, reflect.TypeFor[RequiredConfig]()))
+	assert.Equal(t, "# Port (default 8080)\n# PORT=8080\n\n# Region (required, default us)\nREGION=us\n\n"+
+		"# Database.URL: connection string (required, secret)\nURL=\n", buf.String())
+}
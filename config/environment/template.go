@@ -0,0 +1,76 @@
+package environment
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WriteTemplate writes a .env template of the variables of the struct type
+// t (or pointer to struct), named with the same options as Parse, ready to
+// be filled in. Every variable is preceded by a comment with its field
+// path, `description` tag and notes; required variables and variables
+// without a default are left to fill in, and those with a default are
+// commented out with it, as are the variables of slice and map elements
+// (with their index or key placeholder):
+//
+//	# Database.URL: connection string (required, secret)
+//	DATABASE_URL=
+//
+//	# Port (default 8080)
+//	# PORT=8080
+func WriteTemplate(w io.Writer, t reflect.Type, opts ...Option) error {
+	separator := ""
+
+	for _, variable := range Variables(t, opts...) {
+		if variable.Name == "" {
+			continue
+		}
+
+		line := variable.Name + "="
+		if variable.HasDefault {
+			line += variable.Default
+		}
+
+		if variable.Repeated() || (variable.HasDefault && !variable.Required) {
+			line = "# " + line
+		}
+
+		if _, err := fmt.Fprintf(w, "%s# %s\n%s\n", separator, templateComment(variable), line); err != nil {
+			return err
+		}
+
+		separator = "\n"
+	}
+
+	return nil
+}
+
+// templateComment returns the field path, description and notes of a
+// variable.
+func templateComment(variable Variable) string {
+	comment := variable.Field
+	if description := variable.StructField.Tag.Get("description"); description != "" {
+		comment += ": " + description
+	}
+
+	var notes []string
+	if variable.Required {
+		notes = append(notes, "required")
+	}
+
+	if _, safe := variable.StructField.Tag.Lookup("safe"); safe {
+		notes = append(notes, "secret")
+	}
+
+	if variable.HasDefault {
+		notes = append(notes, "default "+variable.Default)
+	}
+
+	if len(notes) > 0 {
+		comment += " (" + strings.Join(notes, ", ") + ")"
+	}
+
+	return comment
+}
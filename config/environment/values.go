@@ -0,0 +1,229 @@
+package environment
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	timetools "github.com/guionardo/go/time_tools"
+)
+
+type (
+	// valueOptions holds the per-field parsing options read from struct tags.
+	valueOptions struct {
+		separator       string
+		keyValSeparator string
+	}
+)
+
+const (
+	// DefaultSeparator splits slice, set and map entries (tag `envSeparator`).
+	DefaultSeparator = ","
+
+	// DefaultKeyValSeparator splits map keys from values (tag `envKeyValSeparator`).
+	DefaultKeyValSeparator = "="
+)
+
+var (
+	// ErrUnsupportedType is returned for fields whose type cannot be parsed from a string.
+	ErrUnsupportedType = errors.New("unsupported type")
+
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	urlType             = reflect.TypeFor[url.URL]()
+	ipType              = reflect.TypeFor[net.IP]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func getValueOptions(field reflect.StructField) valueOptions {
+	opts := valueOptions{
+		separator:       DefaultSeparator,
+		keyValSeparator: DefaultKeyValSeparator,
+	}
+
+	if separator, ok := field.Tag.Lookup("envSeparator"); ok && separator != "" {
+		opts.separator = separator
+	}
+
+	if separator, ok := field.Tag.Lookup("envKeyValSeparator"); ok && separator != "" {
+		opts.keyValSeparator = separator
+	}
+
+	return opts
+}
+
+// setValue parses raw into v according to its type.
+//
+// Supported: strings, ints, uints, bools, floats, time.Duration, time.Time
+// (any layout known to time_tools.Parse), url.URL, net.IP, types implementing
+// encoding.TextUnmarshaler, pointers to any of these, slices (split by the
+// separator), maps ("k=v" entries split by the separator), sets
+// (map[T]struct{}, e.g. set.Set[T]) and empty interfaces (set to the string).
+func setValue(v reflect.Value, raw string, opts valueOptions) error { //nolint:cyclop,funlen
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return setValue(v.Elem(), raw, opts)
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+
+		return err
+	case timeType:
+		t, err := timetools.Parse(raw)
+		if err == nil {
+			v.Set(reflect.ValueOf(t))
+		}
+
+		return err
+	case urlType:
+		u, err := url.Parse(raw)
+		if err == nil {
+			v.Set(reflect.ValueOf(*u))
+		}
+
+		return err
+	case ipType:
+		ip := net.ParseIP(raw)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address %q", raw)
+		}
+
+		v.Set(reflect.ValueOf(ip))
+
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)) //nolint:forcetypeassert
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintValue, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(uintValue)
+	case reflect.Bool:
+		boolValue, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(boolValue)
+	case reflect.Float64, reflect.Float32:
+		floatValue, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(floatValue)
+	case reflect.Slice:
+		return setSlice(v, raw, opts)
+	case reflect.Map:
+		return setMap(v, raw, opts)
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+		}
+
+		v.Set(reflect.ValueOf(raw))
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, v.Type())
+	}
+
+	return nil
+}
+
+// setSlice parses separator-delimited items. []byte receives raw bytes.
+func setSlice(v reflect.Value, raw string, opts valueOptions) error {
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		v.SetBytes([]byte(raw))
+		return nil
+	}
+
+	items := splitItems(raw, opts.separator)
+	slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+
+	for i, item := range items {
+		if err := setValue(slice.Index(i), item, opts); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	v.Set(slice)
+
+	return nil
+}
+
+// setMap parses "k=v" entries, or bare keys for sets (map[T]struct{}).
+func setMap(v reflect.Value, raw string, opts valueOptions) error {
+	mapType := v.Type()
+	isSet := mapType.Elem().Kind() == reflect.Struct && mapType.Elem().NumField() == 0
+	m := reflect.MakeMap(mapType)
+
+	for _, item := range splitItems(raw, opts.separator) {
+		keyRaw, valueRaw, found := strings.Cut(item, opts.keyValSeparator)
+		if isSet {
+			keyRaw = item
+		} else if !found {
+			return fmt.Errorf("invalid map entry %q: missing %q", item, opts.keyValSeparator)
+		}
+
+		key := reflect.New(mapType.Key()).Elem()
+		if err := setValue(key, strings.TrimSpace(keyRaw), opts); err != nil {
+			return fmt.Errorf("key %q: %w", keyRaw, err)
+		}
+
+		value := reflect.New(mapType.Elem()).Elem()
+		if !isSet {
+			if err := setValue(value, strings.TrimSpace(valueRaw), opts); err != nil {
+				return fmt.Errorf("value for key %q: %w", keyRaw, err)
+			}
+		}
+
+		m.SetMapIndex(key, value)
+	}
+
+	v.Set(m)
+
+	return nil
+}
+
+// splitItems splits raw by separator, trimming spaces and dropping empty items.
+func splitItems(raw, separator string) []string {
+	parts := strings.Split(raw, separator)
+	items := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+
+	return items
+}
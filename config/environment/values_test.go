@@ -0,0 +1,123 @@
+package environment_test
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/set"
+)
+
+type (
+	upperText string
+
+	RichStruct struct {
+		Duration   time.Duration     `env:"RICH_DURATION"`
+		Time       time.Time         `env:"RICH_TIME"`
+		URL        url.URL           `env:"RICH_URL"`
+		URLPtr     *url.URL          `env:"RICH_URL_PTR"`
+		IP         net.IP            `env:"RICH_IP"`
+		Strings    []string          `env:"RICH_STRINGS"`
+		Ints       []int             `env:"RICH_INTS" envSeparator:";"`
+		Durations  []time.Duration   `env:"RICH_DURATIONS"`
+		Bytes      []byte            `env:"RICH_BYTES"`
+		Labels     map[string]string `env:"RICH_LABELS"`
+		Weights    map[string]int    `env:"RICH_WEIGHTS" envSeparator:";" envKeyValSeparator:":"`
+		Tags       set.Set[string]   `env:"RICH_TAGS"`
+		IntPtr     *int              `env:"RICH_INT_PTR"`
+		Text       upperText         `env:"RICH_TEXT"`
+		Default    time.Duration     `env:"RICH_DEFAULT" default:"1m30s"`
+		Unsettable chan int          `env:"RICH_CHAN"`
+	}
+)
+
+func (u *upperText) UnmarshalText(text []byte) error {
+	*u = upperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestParseEnvironment_RichTypes(t *testing.T) {
+	t.Setenv("RICH_DURATION", "5s")
+	t.Setenv("RICH_TIME", "2026-01-02 03:04:05")
+	t.Setenv("RICH_URL", "https://example.com/path?q=1")
+	t.Setenv("RICH_URL_PTR", "postgres://db:5432/app")
+	t.Setenv("RICH_IP", "10.0.0.1")
+	t.Setenv("RICH_STRINGS", "a, b ,c")
+	t.Setenv("RICH_INTS", "1;2;3")
+	t.Setenv("RICH_DURATIONS", "1s,2m")
+	t.Setenv("RICH_BYTES", "raw,bytes")
+	t.Setenv("RICH_LABELS", "env=prod,team=core")
+	t.Setenv("RICH_WEIGHTS", "a:1;b:2")
+	t.Setenv("RICH_TAGS", "x,y,x")
+	t.Setenv("RICH_INT_PTR", "42")
+	t.Setenv("RICH_TEXT", "shout")
+
+	var rs RichStruct
+	require.NoError(t, environment.ParseEnvironment(&rs, nil))
+
+	assert.Equal(t, 5*time.Second, rs.Duration)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), rs.Time)
+	assert.Equal(t, "example.com", rs.URL.Host)
+	assert.Equal(t, "q=1", rs.URL.RawQuery)
+	require.NotNil(t, rs.URLPtr)
+	assert.Equal(t, "postgres", rs.URLPtr.Scheme)
+	assert.True(t, net.ParseIP("10.0.0.1").Equal(rs.IP))
+	assert.Equal(t, []string{"a", "b", "c"}, rs.Strings)
+	assert.Equal(t, []int{1, 2, 3}, rs.Ints)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, rs.Durations)
+	assert.Equal(t, []byte("raw,bytes"), rs.Bytes)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, rs.Labels)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, rs.Weights)
+	assert.True(t, rs.Tags.Equals(set.New("x", "y")))
+	require.NotNil(t, rs.IntPtr)
+	assert.Equal(t, 42, *rs.IntPtr)
+	assert.Equal(t, upperText("SHOUT"), rs.Text)
+	assert.Equal(t, 90*time.Second, rs.Default)
+}
+
+func TestParseEnvironment_RichTypeErrors(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{env: "RICH_DURATION", value: "five seconds"},
+		{env: "RICH_TIME", value: "not a time"},
+		{env: "RICH_URL", value: "://bad"},
+		{env: "RICH_IP", value: "999.0.0.1"},
+		{env: "RICH_INTS", value: "1;two"},
+		{env: "RICH_LABELS", value: "novalue"},
+		{env: "RICH_WEIGHTS", value: "a:x"},
+		{env: "RICH_CHAN", value: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+
+			var rs RichStruct
+			err := environment.ParseEnvironment(&rs, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.value)
+		})
+	}
+
+	t.Run("unsupported_type", func(t *testing.T) {
+		t.Setenv("RICH_CHAN", "1")
+
+		var rs RichStruct
+		require.ErrorIs(t, environment.ParseEnvironment(&rs, nil), environment.ErrUnsupportedType)
+	})
+
+	t.Run("int_overflow", func(t *testing.T) {
+		t.Setenv("INT8", "300")
+
+		var ts TestStruct
+		require.Error(t, environment.ParseEnvironment(&ts, nil))
+	})
+}
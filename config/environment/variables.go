@@ -0,0 +1,106 @@
+package environment
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+type (
+	// Variable describes a struct field read from the environment by Parse.
+	Variable struct {
+		// Field is the dotted Go field path, e.g. "Database.Pool.Size".
+		Field string
+		// Name is the variable name ("" if the field has none and only
+		// receives its `default` tag).
+		Name string
+		// Default is the `default` tag value.
+		Default string
+		// HasDefault reports whether the field has a `default` tag.
+		HasDefault bool
+		// Required reports the required option of the `env` tag.
+		Required bool
+		// StructField is the field declaration, for its type and other tags.
+		StructField reflect.StructField
+	}
+)
+
+// Variables returns the fields of the struct type t (or pointer to struct)
+// that Parse would read with the same options, in declaration order. The
+// fields of the elements of slices and maps of structs are repeated
+// variables, with IndexPlaceholder or KeyPlaceholder in their Field and
+// Name.
+func Variables(t reflect.Type, opts ...Option) []Variable {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	o := newOptions(opts)
+
+	var variables []Variable
+
+	walkVariables(t, "", o.prefix, o, &variables)
+
+	return variables
+}
+
+func walkVariables(t reflect.Type, parentPath, prefix string, o options, variables *[]Variable) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("env") == skipTag {
+			continue
+		}
+
+		path := joinPath(parentPath, field.Name)
+
+		if field.Type.Kind() == reflect.Struct && !structfield.IsScalarStruct(field.Type) {
+			walkVariables(field.Type, path, prefix+o.structPrefix(field), o, variables)
+			continue
+		}
+
+		if elem := elemStruct(field.Type); elem != nil && o.collectionPrefix(field) != "" {
+			element := placeholder(field.Type)
+			walkVariables(elem, joinPath(path, element), prefix+o.collectionPrefix(field)+element+"_", o, variables)
+
+			continue
+		}
+
+		*variables = append(*variables, newVariable(field, path, o.envName(field, prefix)))
+	}
+}
+
+// Repeated reports a variable of the elements of a slice or map of
+// structs, whose Field and Name have an IndexPlaceholder or KeyPlaceholder.
+func (v Variable) Repeated() bool {
+	return strings.Contains(v.Field, IndexPlaceholder) || strings.Contains(v.Field, KeyPlaceholder)
+}
+
+// Match reports whether name is the variable, with any index or key in
+// place of the placeholders of a repeated variable.
+func (v Variable) Match(name string) bool {
+	if !v.Repeated() {
+		return name != "" && name == v.Name
+	}
+
+	return regexp.MustCompile("^" + namePattern(v.Name) + "$").MatchString(name)
+}
+
+func newVariable(field reflect.StructField, path, name string) Variable {
+	defaultValue, hasDefault := field.Tag.Lookup("default")
+	_, required := envTag(field)
+
+	return Variable{
+		Field:       path,
+		Name:        name,
+		Default:     defaultValue,
+		HasDefault:  hasDefault,
+		Required:    required,
+		StructField: field,
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+type (
+	// Format is the encoding of an exported configuration.
+	Format string
+)
+
+const (
+	// FormatYAML exports the configuration as YAML, shaped like the profiles.
+	FormatYAML Format = "yaml"
+	// FormatJSON exports the configuration as indented JSON.
+	FormatJSON Format = "json"
+)
+
+// ErrUnknownFormat is returned for an export format other than FormatYAML
+// and FormatJSON.
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Export loads the configuration of T exactly as a Provider built with
+// options would (profiles, sources, secrets, environment), writes it to w
+// with `safe` fields and resolved secrets masked, and validates it.
+//
+// Unlike GetConfiguration, a missing profile is an error and the
+// configuration is written even when it is invalid, so the returned error
+// (load and validation errors, joined) can be shown next to it. It is meant
+// for CI checks of the profiles of every scope:
+//
+//	err := config.Export[AppConfig](os.Stdout, config.FormatYAML,
+//	    config.WithProfilesPath("CONFIGS"), config.WithScope("production"))
+func Export[T any](w io.Writer, format Format, options ...providerOption) error {
+	p := NewProvider[T](options...)
+
+	configuration, meta, loadErr := p.readConfiguration(context.Background(), true)
+	if err := writeConfiguration(w, format, configuration, meta.secretPaths); err != nil {
+		return err
+	}
+
+	return errors.Join(loadErr, p.validateConfiguration(configuration))
+}
+
+// Export writes the current configuration to w, loading it first if
+// needed, with `safe` fields and resolved secrets masked.
+func (p *Provider[T]) Export(w io.Writer, format Format) error {
+	configuration, err := p.GetConfiguration()
+	if err != nil {
+		return err
+	}
+
+	p.lock.RLock()
+	secretPaths := p.meta.secretPaths
+	p.lock.RUnlock()
+
+	return writeConfiguration(w, format, configuration, secretPaths)
+}
+
+// WriteEnvTemplate writes a ready-to-fill .env template of the environment
+// variables of T, named like a Provider built with options would read them
+// (WithEnvPrefix, WithEnvAutoNaming); see environment.WriteTemplate.
+func WriteEnvTemplate[T any](w io.Writer, options ...providerOption) error {
+	p := NewProvider[T](options...)
+
+	return environment.WriteTemplate(w, reflect.TypeFor[T](), p.envOptions...)
+}
+
+// writeConfiguration encodes the masked export map of configuration to w.
+func writeConfiguration(w io.Writer, format Format, configuration any, masked []string) error {
+	values := exportValue(reflect.ValueOf(configuration), "", maskedFields(masked))
+
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2) //nolint:mnd
+
+		if err := encoder.Encode(values); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(values)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// maskedFields returns the mask of exportValue hiding `safe` fields and the
+// fields at or below the masked paths.
+func maskedFields(masked []string) func(path string, safe bool) bool {
+	return func(path string, safe bool) bool {
+		return safe || isMaskedPath(path, masked)
+	}
+}
+
+// exportValue converts v to maps keyed by the yaml field names, as the
+// profiles are written, replacing the fields selected by mask with
+// maskedValue. Durations are written as strings ("30s") so both formats
+// read back.
+func exportValue(v reflect.Value, path string, mask func(path string, safe bool) bool) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		return time.Duration(v.Int()).String()
+	case isFieldStruct(v.Type()):
+		values := map[string]any{}
+		exportStruct(v, path, mask, values)
+
+		return values
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8, v.Kind() == reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = exportValue(v.Index(i), path, mask)
+		}
+
+		return items
+	case v.Kind() == reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		values := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			values[fmt.Sprint(iter.Key().Interface())] = exportValue(iter.Value(), path, mask)
+		}
+
+		return values
+	default:
+		return v.Interface()
+	}
+}
+
+// exportStruct adds the fields of the struct v to values, inlined structs
+// included.
+func exportStruct(
+	v reflect.Value,
+	parentPath string,
+	mask func(path string, safe bool) bool,
+	values map[string]any,
+) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, inline, skip := structfield.YAMLName(field)
+		if skip || !field.IsExported() {
+			continue
+		}
+
+		path := fieldPath(parentPath, field.Name)
+
+		_, safe := field.Tag.Lookup("safe")
+		switch {
+		case mask(path, safe):
+			values[name] = maskedValue
+		case inline && isFieldStruct(field.Type):
+			exportStruct(v.Field(i), path, mask, values)
+		default:
+			values[name] = exportValue(v.Field(i), path, mask)
+		}
+	}
+}
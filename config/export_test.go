@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	ExportBase struct {
+		Region string `yaml:"region"`
+	}
+
+	exportConfig struct {
+		ExportBase `yaml:",inline"`
+
+		Name     string        `yaml:"name" validate:"required"`
+		Timeout  time.Duration `yaml:"timeout"`
+		Token    string        `yaml:"token" safe:"true"`
+		Password string        `yaml:"password"`
+		Servers  []struct {
+			Host string `yaml:"host"`
+		} `yaml:"servers"`
+		Internal string `yaml:"-"`
+	}
+)
+
+func writeExportProfiles(t *testing.T, profiles map[string]string) string {
+	t.Helper()
+
+	tmp := t.TempDir()
+	for scope, content := range profiles {
+		require.NoError(t, os.WriteFile(path.Join(tmp, scope+".yml"), []byte(content), 0600))
+	}
+
+	return tmp
+}
+
+func TestExport(t *testing.T) {
+	t.Setenv("EXPORT_TEST_PASSWORD", "s3cr3t")
+
+	profiles := writeExportProfiles(t, map[string]string{
+		"default": "region: sa-east-1\nname: app\ntimeout: 30s\ntoken: abc\n" +
+			"password: ${env:EXPORT_TEST_PASSWORD}\nservers:\n  - host: a\n",
+		"broken": "name: ''\n",
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, Export[exportConfig](&out, FormatYAML,
+			WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("default")))
+		assert.Equal(t, "name: app\npassword: '********'\nregion: sa-east-1\nservers:\n  - host: a\n"+
+			"timeout: 30s\ntoken: '********'\n", out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, Export[exportConfig](&out, FormatJSON,
+			WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("default")))
+
+		var values map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &values))
+		assert.Equal(t, "30s", values["timeout"])
+		assert.Equal(t, maskedValue, values["password"])
+		assert.Equal(t, []any{map[string]any{"host": "a"}}, values["servers"])
+	})
+
+	t.Run("invalid_configuration_is_written_and_reported", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := Export[exportConfig](&out, FormatYAML,
+			WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("broken"))
+		require.Error(t, err)
+		assert.Contains(t, out.String(), "name: \"\"\n")
+	})
+
+	t.Run("missing_profile_is_an_error", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := Export[exportConfig](&out, FormatYAML,
+			WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("staging"))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		err := Export[exportConfig](&bytes.Buffer{}, "toml",
+			WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("default"))
+		require.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
+
+func TestProviderExport(t *testing.T) {
+	t.Parallel()
+
+	profiles := writeExportProfiles(t, map[string]string{"default": "name: app\ntoken: abc\n"})
+	provider := NewProvider[exportConfig](
+		WithProfilesPath(profiles), WithDefaultScope("default"), WithScope("default"))
+
+	var out bytes.Buffer
+
+	require.NoError(t, provider.Export(&out, FormatYAML))
+	assert.Contains(t, out.String(), "token: '********'\n")
+	assert.NotContains(t, out.String(), "internal")
+}
+
+func TestWriteEnvTemplate(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteEnvTemplate[requiredEnvConfig](&buf, WithEnvPrefix("APP_")))
+	assert.Equal(t, "# Name (required)\nAPP_NAME=\n\n# Token (required, secret)\nAPP_TOKEN=\n", buf.String())
+}
@@ -0,0 +1,260 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/guionardo/go/config"
+)
+
+type (
+	// Client evaluates the flags of a configuration provider, following
+	// its reloads and updates.
+	Client struct {
+		options
+		flags       atomic.Pointer[Set]
+		unsubscribe func()
+	}
+
+	// Option configures a Client.
+	Option func(*options)
+
+	// Logger receives the refresh errors and the evaluations of
+	// LogObserver; *slog.Logger and config.Logger implementations
+	// satisfy it.
+	Logger interface {
+		Debug(msg string, args ...any)
+		Info(msg string, args ...any)
+		Warn(msg string, args ...any)
+		Error(msg string, args ...any)
+	}
+
+	options struct {
+		envPrefix string
+		lookup    func(name string) (string, bool)
+		environ   []string
+		observers []func(Evaluation)
+		logger    Logger
+	}
+)
+
+// DefaultEnvPrefix prefixes the environment variables overriding flags:
+// FLAG_NEW_CHECKOUT=off overrides the flag "new-checkout".
+const DefaultEnvPrefix = "FLAG_"
+
+// WithEnvPrefix sets the prefix of the environment variables overriding
+// flags (default DefaultEnvPrefix).
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithLookup replaces os.LookupEnv as the source of the overriding
+// variables.
+func WithLookup(lookup func(name string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookup = lookup
+	}
+}
+
+// WithEnviron sets the variable names searched for flags that are only
+// declared in the environment (default: the names in os.Environ), e.g. the
+// names visible through WithLookup.
+func WithEnviron(names []string) Option {
+	return func(o *options) {
+		o.environ = names
+	}
+}
+
+// WithLogger sets the logger of the refresh errors (defaults to
+// slog.Default()).
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithObserver calls fn with every evaluation, e.g. to audit or count them.
+// Observers run synchronously on the evaluating goroutine.
+func WithObserver(fn func(Evaluation)) Option {
+	return func(o *options) {
+		o.observers = append(o.observers, fn)
+	}
+}
+
+// LogObserver returns an observer logging every evaluation at info level.
+func LogObserver(logger Logger) func(Evaluation) {
+	return func(e Evaluation) {
+		logger.Info("feature flag evaluated",
+			slog.String("flag", e.Flag),
+			slog.Bool("enabled", e.Enabled),
+			slog.String("variant", e.Variant),
+			slog.String("reason", string(e.Reason)),
+			slog.String("user", e.Context.UserID),
+			slog.String("tenant", e.Context.Tenant))
+	}
+}
+
+// New returns a client for the flags that flagsOf returns from the
+// configuration of p, loading it if needed. The flags are refreshed on
+// every configuration change (Watch reloads and UpdateConfiguration) until
+// Close. Environment variables named after the flags (see WithEnvPrefix)
+// override their declarations, in any form UnmarshalText accepts; the
+// other prefixed variables declare flags of their own, named in lower case
+// with "-" for "_" (FLAG_DARK_MODE=on declares "dark-mode").
+func New[T any](p *config.Provider[T], flagsOf func(T) Set, opts ...Option) (*Client, error) {
+	c := &Client{options: options{envPrefix: DefaultEnvPrefix, lookup: os.LookupEnv, logger: slog.Default()}}
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+
+	configuration, err := p.GetConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("config/flags: %w", err)
+	}
+
+	if err := c.refresh(flagsOf(configuration)); err != nil {
+		return nil, err
+	}
+
+	c.unsubscribe = p.Subscribe(func(_, configuration T) {
+		if err := c.refresh(flagsOf(configuration)); err != nil {
+			c.logger.Warn("invalid feature flags, keeping current", "error", err)
+		}
+	})
+
+	return c, nil
+}
+
+// Close stops following the provider; the flags keep their last values.
+func (c *Client) Close() {
+	c.unsubscribe()
+}
+
+// Enabled reports whether the flag is on for the evaluation context of ctx
+// (see NewContext). Unknown flags are off.
+func (c *Client) Enabled(ctx context.Context, name string) bool {
+	return c.Evaluate(ctx, name).Enabled
+}
+
+// Variant returns the variant of the flag for the evaluation context of
+// ctx, or fallback when the flag is off or has no variants.
+func (c *Client) Variant(ctx context.Context, name, fallback string) string {
+	if variant := c.Evaluate(ctx, name).Variant; variant != "" {
+		return variant
+	}
+
+	return fallback
+}
+
+// Evaluate evaluates the flag for the evaluation context of ctx and reports
+// the evaluation to the observers.
+func (c *Client) Evaluate(ctx context.Context, name string) Evaluation {
+	fc := FromContext(ctx)
+
+	flag, ok := (*c.flags.Load())[name]
+
+	evaluation := Evaluation{Flag: name, Context: fc, Reason: ReasonUnknown}
+	if ok {
+		evaluation = flag.Evaluate(name, fc)
+	}
+
+	evaluation.Time = time.Now()
+
+	for _, observer := range c.observers {
+		observer(evaluation)
+	}
+
+	return evaluation
+}
+
+// Flags returns the current flags, environment overrides included.
+func (c *Client) Flags() Set {
+	return *c.flags.Load()
+}
+
+// refresh replaces the flags with flags, their environment overrides and
+// the flags declared in the environment only.
+func (c *Client) refresh(flags Set) error {
+	current := make(Set, len(flags))
+	declared := make(map[string]bool, len(flags))
+
+	for name, flag := range flags {
+		current[name] = flag
+		declared[c.envName(name)] = true
+	}
+
+	for name := range flags {
+		if err := c.override(current, name, c.envName(name)); err != nil {
+			return err
+		}
+	}
+
+	for _, variable := range c.names() {
+		suffix, ok := strings.CutPrefix(variable, c.envPrefix)
+		if !ok || suffix == "" || declared[variable] {
+			continue
+		}
+
+		if err := c.override(current, strings.ToLower(strings.ReplaceAll(suffix, "_", "-")), variable); err != nil {
+			return err
+		}
+	}
+
+	c.flags.Store(&current)
+
+	return nil
+}
+
+// override sets the flag name of current from the variable, if it is set.
+func (c *Client) override(current Set, name, variable string) error {
+	raw, ok := c.lookup(variable)
+	if !ok {
+		return nil
+	}
+
+	var flag Flag
+	if err := flag.UnmarshalText([]byte(raw)); err != nil {
+		return fmt.Errorf("config/flags: %s: %w", variable, err)
+	}
+
+	current[name] = flag
+
+	return nil
+}
+
+// names returns the variable names searched for flags declared in the
+// environment only.
+func (c *Client) names() []string {
+	if c.environ != nil {
+		return c.environ
+	}
+
+	names := make([]string, 0, len(os.Environ()))
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// envName returns the overriding variable of a flag: the prefix and the
+// name in upper case, other characters than letters and digits replaced
+// by "_" ("new-checkout" → FLAG_NEW_CHECKOUT).
+func (c *Client) envName(name string) string {
+	return c.envPrefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+}
@@ -0,0 +1,42 @@
+// Package flags evaluates feature flags declared in the configuration
+// profiles of a config.Provider.
+//
+// Flags are a Set field of the configuration struct, so they are merged,
+// scoped and reloaded like any other setting:
+//
+//	type AppConfig struct {
+//	    Flags flags.Set `yaml:"flags"`
+//	}
+//
+//	# production.yml
+//	flags:
+//	  new-checkout: true            # on for everyone
+//	  search-v2: 25%                # rollout to a quarter of the users
+//	  button-color: blue:50,green:50 # weighted variants
+//	  beta:
+//	    percentage: 10
+//	    users: [alice]              # always on for alice
+//	    attributes: {plan: [pro]}   # only for pro plans
+//
+// Environment variables override single flags without a redeploy:
+// FLAG_SEARCH_V2=off switches "search-v2" off (see WithEnvPrefix), and
+// FLAG_DARK_MODE=on declares "dark-mode" when no profile does.
+//
+// A Client follows the provider: every Watch reload or UpdateConfiguration
+// replaces the flags atomically. Flags are evaluated for the Context
+// carried by a context.Context; percentage rollouts and variants hash the
+// flag name with the user id (or tenant), so a user keeps the same answer
+// while the percentage does not shrink:
+//
+//	client, err := flags.New(provider, func(c AppConfig) flags.Set { return c.Flags },
+//	    flags.WithObserver(flags.LogObserver(auditLogger)))
+//	defer client.Close()
+//
+//	ctx = flags.NewContext(ctx, flags.Context{UserID: "42", Tenant: "acme",
+//	    Attributes: map[string]string{"plan": "pro"}})
+//	if client.Enabled(ctx, "new-checkout") { ... }
+//	color := client.Variant(ctx, "button-color", "blue")
+//
+// Every evaluation (flag, context, result, variant and Reason) is given to
+// the WithObserver functions, for auditing or metrics.
+package flags
@@ -0,0 +1,154 @@
+package flags
+
+import (
+	"context"
+	"hash/fnv"
+	"io"
+	"slices"
+	"time"
+)
+
+type (
+	// Context is who a flag is evaluated for. UserID (or Tenant, without
+	// a user) is the rollout key: the same key always gets the same
+	// answer for a flag.
+	Context struct {
+		UserID     string
+		Tenant     string
+		Attributes map[string]string
+	}
+
+	// Reason explains an evaluation result.
+	Reason string
+
+	// Evaluation is the result of a flag evaluation, as given to the
+	// observers.
+	Evaluation struct {
+		Flag    string
+		Context Context
+		Enabled bool
+		// Variant is the variant given, "" when the flag is off or has
+		// no variants.
+		Variant string
+		Reason  Reason
+		Time    time.Time
+	}
+
+	contextKey struct{}
+)
+
+const (
+	// ReasonUnknown: the flag is not declared, it is off.
+	ReasonUnknown Reason = "unknown"
+	// ReasonDisabled: the flag is switched off.
+	ReasonDisabled Reason = "disabled"
+	// ReasonTarget: the user or tenant is listed in the flag.
+	ReasonTarget Reason = "target"
+	// ReasonMismatch: the context attributes do not match the flag's.
+	ReasonMismatch Reason = "mismatch"
+	// ReasonRollout: the percentage rollout decided.
+	ReasonRollout Reason = "rollout"
+	// ReasonEnabled: the flag is on for everyone.
+	ReasonEnabled Reason = "enabled"
+
+	// buckets is the rollout resolution: 0.01%.
+	buckets = 10000
+)
+
+// NewContext returns a copy of ctx carrying the evaluation context.
+func NewContext(ctx context.Context, fc Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, fc)
+}
+
+// FromContext returns the evaluation context carried by ctx (empty if none).
+func FromContext(ctx context.Context) Context {
+	fc, _ := ctx.Value(contextKey{}).(Context)
+
+	return fc
+}
+
+// Evaluate evaluates the flag for fc. Users and tenants listed in the flag
+// get it; other contexts must match its attributes and fall in its rollout
+// percentage. Variants are chosen by weight from the same rollout key.
+func (f Flag) Evaluate(name string, fc Context) Evaluation {
+	e := Evaluation{Flag: name, Context: fc}
+
+	switch {
+	case !f.Enabled:
+		e.Reason = ReasonDisabled
+	case fc.UserID != "" && slices.Contains(f.Users, fc.UserID),
+		fc.Tenant != "" && slices.Contains(f.Tenants, fc.Tenant):
+		e.Enabled, e.Reason = true, ReasonTarget
+	case !f.matches(fc.Attributes):
+		e.Reason = ReasonMismatch
+	case f.Percentage >= maxPercentage:
+		e.Enabled, e.Reason = true, ReasonEnabled
+	default:
+		key := fc.rolloutKey()
+		e.Enabled = key != "" && bucket(name, key) < int(f.Percentage*buckets/maxPercentage)
+		e.Reason = ReasonRollout
+	}
+
+	if e.Enabled {
+		e.Variant = f.variant(name, fc.rolloutKey())
+	}
+
+	return e
+}
+
+// matches reports whether attributes has one of the values of every flag
+// attribute.
+func (f Flag) matches(attributes map[string]string) bool {
+	for name, values := range f.Attributes {
+		value, ok := attributes[name]
+		if !ok || !slices.Contains(values, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// variant returns the variant of key, chosen by weight in name order.
+func (f Flag) variant(name, key string) string {
+	names := make([]string, 0, len(f.Variants))
+	total := 0
+
+	for variant, weight := range f.Variants {
+		names = append(names, variant)
+		total += weight
+	}
+
+	if total == 0 {
+		return ""
+	}
+
+	slices.Sort(names)
+
+	point := bucket(name+"/variant", key) * total / buckets
+	for _, variant := range names {
+		if point < f.Variants[variant] {
+			return variant
+		}
+
+		point -= f.Variants[variant]
+	}
+
+	return names[len(names)-1]
+}
+
+func (fc Context) rolloutKey() string {
+	if fc.UserID != "" {
+		return fc.UserID
+	}
+
+	return fc.Tenant
+}
+
+// bucket hashes the flag name and key to [0, buckets).
+func bucket(name, key string) int {
+	hash := fnv.New32a()
+	_, _ = io.WriteString(hash, name+"\x00"+key)
+
+	return int(hash.Sum32() % buckets)
+}
@@ -0,0 +1,27 @@
+package flags_test
+
+import (
+	"fmt"
+
+	"github.com/guionardo/go/config/flags"
+)
+
+func ExampleFlag_Evaluate() {
+	var flag flags.Flag
+	if err := flag.UnmarshalText([]byte("blue:1,green:1")); err != nil {
+		panic(err)
+	}
+
+	flag.Attributes = map[string][]string{"plan": {"pro"}}
+
+	pro := flags.Context{UserID: "42", Attributes: map[string]string{"plan": "pro"}}
+	free := flags.Context{UserID: "42", Attributes: map[string]string{"plan": "free"}}
+
+	for _, fc := range []flags.Context{pro, free} {
+		evaluation := flag.Evaluate("button-color", fc)
+		fmt.Println(evaluation.Enabled, evaluation.Reason, evaluation.Variant != "")
+	}
+	// Output:
+	// true enabled true
+	// false mismatch false
+}
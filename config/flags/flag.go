@@ -0,0 +1,164 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Flag is a feature flag definition. In the profiles it is either a
+	// scalar (see UnmarshalText) or a mapping of these fields:
+	//
+	//	flags:
+	//	  new-checkout: true
+	//	  search-v2: 25%
+	//	  button-color: blue:50,green:50
+	//	  beta:
+	//	    percentage: 10
+	//	    users: [alice]
+	//	    tenants: [acme]
+	//	    attributes: {plan: [pro, enterprise]}
+	//
+	// A mapping is enabled for everyone unless it says otherwise
+	// (enabled: false, percentage or attributes).
+	Flag struct {
+		// Enabled switches the flag off for everyone when false.
+		Enabled bool `yaml:"enabled"`
+		// Percentage is the share of the users (or tenants), from 0 to
+		// 100, that get the flag.
+		Percentage float64 `yaml:"percentage"`
+		// Variants are the weights of the variants given to the users that
+		// get the flag.
+		Variants map[string]int `yaml:"variants"`
+		// Users always get the flag.
+		Users []string `yaml:"users"`
+		// Tenants always get the flag.
+		Tenants []string `yaml:"tenants"`
+		// Attributes restrict the flag to the contexts whose attributes
+		// have one of the listed values, for every listed attribute.
+		Attributes map[string][]string `yaml:"attributes"`
+	}
+
+	// Set is the feature flags by name, as declared in a configuration
+	// struct:
+	//
+	//	type AppConfig struct {
+	//	    Flags flags.Set `yaml:"flags"`
+	//	}
+	Set map[string]Flag
+
+	// flagFields is Flag without its methods, for the mapping form.
+	flagFields Flag
+)
+
+const (
+	percentSuffix    = "%"
+	variantSeparator = ","
+	weightSeparator  = ":"
+	maxPercentage    = 100
+)
+
+// ErrInvalidFlag is returned for a flag that cannot be parsed.
+var ErrInvalidFlag = errors.New("invalid flag")
+
+// UnmarshalText parses the scalar form of a flag, as written in a profile
+// or an environment variable:
+//   - a boolean ("true", "false", "on", "off", "1", "0"): on or off for
+//     everyone
+//   - a percentage ("25%"): a rollout to that share of the users
+//   - weighted variants ("blue:50,green:50"): on for everyone, with the
+//     variants given by weight; every variant needs its weight
+//
+// Any other value (e.g. "disabled", "none" or a typo of "enabled") is an
+// ErrInvalidFlag, so a mistake never turns a flag on.
+func (f *Flag) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+
+	switch strings.ToLower(value) {
+	case "true", "on", "yes", "1":
+		*f = Flag{Enabled: true, Percentage: maxPercentage}
+		return nil
+	case "false", "off", "no", "0":
+		*f = Flag{}
+		return nil
+	}
+
+	if percentage, ok := strings.CutSuffix(value, percentSuffix); ok {
+		p, err := strconv.ParseFloat(strings.TrimSpace(percentage), 64)
+		if err != nil || p < 0 || p > maxPercentage {
+			return fmt.Errorf("%w percentage %q", ErrInvalidFlag, value)
+		}
+
+		*f = Flag{Enabled: true, Percentage: p}
+
+		return nil
+	}
+
+	variants, err := parseVariants(value)
+	if err != nil {
+		return err
+	}
+
+	*f = Flag{Enabled: true, Percentage: maxPercentage, Variants: variants}
+
+	return nil
+}
+
+// UnmarshalYAML reads the scalar form (see UnmarshalText) or the mapping
+// form of a flag.
+func (f *Flag) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return f.UnmarshalText([]byte(node.Value))
+	}
+
+	fields := flagFields{Enabled: true, Percentage: maxPercentage}
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+
+	if fields.Percentage < 0 || fields.Percentage > maxPercentage {
+		return fmt.Errorf("%w percentage %v", ErrInvalidFlag, fields.Percentage)
+	}
+
+	*f = Flag(fields)
+
+	return nil
+}
+
+// Names returns the flag names, sorted.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// parseVariants parses "name:weight" items separated by commas.
+func parseVariants(value string) (map[string]int, error) {
+	variants := map[string]int{}
+
+	for item := range strings.SplitSeq(value, variantSeparator) {
+		name, weight, found := strings.Cut(strings.TrimSpace(item), weightSeparator)
+		if name == "" || !found {
+			return nil, fmt.Errorf("%w %q", ErrInvalidFlag, value)
+		}
+
+		w, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("%w variant weight %q", ErrInvalidFlag, item)
+		}
+
+		variants[strings.TrimSpace(name)] = w
+	}
+
+	return variants, nil
+}
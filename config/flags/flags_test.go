@@ -0,0 +1,237 @@
+package flags_test
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config"
+	"github.com/guionardo/go/config/flags"
+)
+
+type appConfig struct {
+	Flags flags.Set `yaml:"flags"`
+}
+
+const profile = `flags:
+  checkout: true
+  legacy: off
+  search: 25%
+  color: blue:1,green:3
+  beta:
+    percentage: 0
+    users: [alice]
+    tenants: [acme]
+  pro:
+    attributes: {plan: [pro, enterprise]}
+  paused:
+    enabled: false
+    users: [alice]
+`
+
+func newProvider(t *testing.T, content string) *config.Provider[appConfig] {
+	t.Helper()
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"), []byte(content), 0o600))
+
+	return config.NewProvider[appConfig](
+		config.WithProfilesPath(tmp),
+		config.WithDefaultScope("default"),
+		config.WithScope("default"),
+	)
+}
+
+func flagsOf(c appConfig) flags.Set {
+	return c.Flags
+}
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func userContext(userID string) context.Context {
+	return flags.NewContext(context.Background(), flags.Context{UserID: userID})
+}
+
+func TestFlag_Parse(t *testing.T) {
+	t.Parallel()
+
+	client, err := flags.New(newProvider(t, profile), flagsOf, flags.WithLookup(noEnv))
+	require.NoError(t, err)
+	defer client.Close()
+
+	set := client.Flags()
+	assert.Equal(t, []string{"beta", "checkout", "color", "legacy", "paused", "pro", "search"}, set.Names())
+	assert.Equal(t, flags.Flag{Enabled: true, Percentage: 100}, set["checkout"])
+	assert.Equal(t, flags.Flag{}, set["legacy"])
+	assert.Equal(t, flags.Flag{Enabled: true, Percentage: 25}, set["search"])
+	assert.Equal(t, map[string]int{"blue": 1, "green": 3}, set["color"].Variants)
+	assert.Equal(t, flags.Flag{Enabled: true, Users: []string{"alice"}, Tenants: []string{"acme"}}, set["beta"])
+	assert.Equal(t, float64(100), set["pro"].Percentage)
+	assert.False(t, set["paused"].Enabled)
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, text := range []string{
+			"150%", "x%", "blue:-1", ":3", "blue:x",
+			"disabled", "none", "enabeld", "", "blue", "blue:50,green",
+		} {
+			var flag flags.Flag
+			require.ErrorIs(t, flag.UnmarshalText([]byte(text)), flags.ErrInvalidFlag, text)
+			assert.False(t, flag.Enabled, text)
+		}
+
+		_, err := flags.New(newProvider(t, "flags:\n  bad:\n    percentage: 101\n"), flagsOf)
+		require.Error(t, err)
+	})
+}
+
+func TestClient_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	var evaluations []flags.Evaluation
+
+	client, err := flags.New(newProvider(t, profile), flagsOf, flags.WithLookup(noEnv),
+		flags.WithObserver(func(e flags.Evaluation) { evaluations = append(evaluations, e) }))
+	require.NoError(t, err)
+	defer client.Close()
+
+	for _, tt := range []struct {
+		flag    string
+		fc      flags.Context
+		enabled bool
+		reason  flags.Reason
+	}{
+		{"checkout", flags.Context{}, true, flags.ReasonEnabled},
+		{"legacy", flags.Context{UserID: "alice"}, false, flags.ReasonDisabled},
+		{"missing", flags.Context{UserID: "alice"}, false, flags.ReasonUnknown},
+		{"beta", flags.Context{UserID: "alice"}, true, flags.ReasonTarget},
+		{"beta", flags.Context{UserID: "bob", Tenant: "acme"}, true, flags.ReasonTarget},
+		{"beta", flags.Context{UserID: "bob"}, false, flags.ReasonRollout},
+		{"pro", flags.Context{Attributes: map[string]string{"plan": "pro"}}, true, flags.ReasonEnabled},
+		{"pro", flags.Context{Attributes: map[string]string{"plan": "free"}}, false, flags.ReasonMismatch},
+		{"pro", flags.Context{}, false, flags.ReasonMismatch},
+		{"paused", flags.Context{UserID: "alice"}, false, flags.ReasonDisabled},
+		{"search", flags.Context{}, false, flags.ReasonRollout},
+	} {
+		t.Run(fmt.Sprintf("%s_%s_%s", tt.flag, tt.fc.UserID, tt.reason), func(t *testing.T) {
+			evaluation := client.Evaluate(flags.NewContext(t.Context(), tt.fc), tt.flag)
+			assert.Equal(t, tt.enabled, evaluation.Enabled)
+			assert.Equal(t, tt.reason, evaluation.Reason)
+			assert.Equal(t, tt.fc, evaluation.Context)
+			assert.False(t, evaluation.Time.IsZero())
+		})
+	}
+
+	assert.Len(t, evaluations, 11)
+	assert.Equal(t, "checkout", evaluations[0].Flag)
+}
+
+func TestClient_Rollout(t *testing.T) {
+	t.Parallel()
+
+	client, err := flags.New(newProvider(t, profile), flagsOf, flags.WithLookup(noEnv))
+	require.NoError(t, err)
+	defer client.Close()
+
+	enabled := 0
+	variants := map[string]int{}
+
+	for i := range 2000 {
+		ctx := userContext(fmt.Sprint("user-", i))
+
+		on := client.Enabled(ctx, "search")
+		assert.Equal(t, on, client.Enabled(ctx, "search"), "deterministic")
+
+		if on {
+			enabled++
+		}
+
+		variants[client.Variant(ctx, "color", "none")]++
+	}
+
+	assert.InDelta(t, 500, enabled, 75)
+	assert.InDelta(t, 500, variants["blue"], 75)
+	assert.InDelta(t, 1500, variants["green"], 75)
+	assert.Equal(t, "none", client.Variant(userContext("alice"), "checkout", "none"))
+	assert.Equal(t, "none", client.Variant(userContext("alice"), "legacy", "none"))
+}
+
+func TestClient_EnvOverride(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"APP_FLAG_CHECKOUT": "off", "APP_FLAG_SEARCH": "100%"}
+
+	client, err := flags.New(newProvider(t, profile), flagsOf, flags.WithEnvPrefix("APP_FLAG_"),
+		flags.WithLookup(func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.False(t, client.Enabled(userContext("alice"), "checkout"))
+	assert.True(t, client.Enabled(userContext("alice"), "search"))
+
+	env["APP_FLAG_LEGACY"] = "maybe%"
+	_, err = flags.New(newProvider(t, profile), flagsOf, flags.WithEnvPrefix("APP_FLAG_"),
+		flags.WithLookup(func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}))
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+}
+
+func TestClient_EnvOnlyFlags(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"APP_FLAG_DARK_MODE": "on", "APP_FLAG_CHECKOUT": "off", "OTHER": "on"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	client, err := flags.New(newProvider(t, profile), flagsOf, flags.WithEnvPrefix("APP_FLAG_"),
+		flags.WithLookup(lookup), flags.WithEnviron(slices.Collect(maps.Keys(env))))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.True(t, client.Enabled(userContext("alice"), "dark-mode"), "declared in the environment only")
+	assert.False(t, client.Enabled(userContext("alice"), "checkout"))
+	assert.Equal(t, []string{"beta", "checkout", "color", "dark-mode", "legacy", "paused", "pro", "search"},
+		client.Flags().Names())
+
+	env["APP_FLAG_TYPO"] = "enabeld"
+	_, err = flags.New(newProvider(t, profile), flagsOf, flags.WithEnvPrefix("APP_FLAG_"),
+		flags.WithLookup(lookup), flags.WithEnviron(slices.Collect(maps.Keys(env))))
+	require.ErrorIs(t, err, flags.ErrInvalidFlag)
+}
+
+func TestClient_FollowsProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := newProvider(t, profile)
+
+	client, err := flags.New(provider, flagsOf, flags.WithLookup(noEnv))
+	require.NoError(t, err)
+
+	ctx := userContext("alice")
+	require.True(t, client.Enabled(ctx, "checkout"))
+
+	require.NoError(t, provider.UpdateConfiguration(appConfig{Flags: flags.Set{"checkout": {}}}))
+	assert.False(t, client.Enabled(ctx, "checkout"))
+
+	client.Close()
+
+	require.NoError(t, provider.UpdateConfiguration(appConfig{Flags: flags.Set{"checkout": {Enabled: true, Percentage: 100}}}))
+	assert.False(t, client.Enabled(ctx, "checkout"))
+}
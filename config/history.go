@@ -0,0 +1,228 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+type (
+	// SnapshotSource tells what produced a configuration snapshot.
+	SnapshotSource string
+
+	// Snapshot is a validated configuration kept in the provider history.
+	// Configuration is a deep copy: changing it does not affect the
+	// provider or the history.
+	Snapshot[T any] struct {
+		// Version numbers the snapshots of a provider from 1, in order.
+		Version uint64
+		// Time is when the configuration was stored.
+		Time time.Time
+		// Source is what stored it: load, reload, update or rollback.
+		Source        SnapshotSource
+		Configuration T
+	}
+
+	// snapshotEntry is a history entry, with the metadata restored by
+	// Rollback.
+	snapshotEntry[T any] struct {
+		Snapshot[T]
+
+		meta loadMeta
+	}
+)
+
+const (
+	// SnapshotLoad is the first configuration, read by GetConfiguration
+	// or Load.
+	SnapshotLoad SnapshotSource = "load"
+	// SnapshotReload is a configuration read again by Reload or a Watch
+	// reload (profile files or sources changed).
+	SnapshotReload SnapshotSource = "reload"
+	// SnapshotUpdate is a configuration set by UpdateConfiguration.
+	SnapshotUpdate SnapshotSource = "update"
+	// SnapshotRollback is an earlier configuration restored by Rollback.
+	SnapshotRollback SnapshotSource = "rollback"
+
+	// DefaultHistorySize is the number of snapshots kept by default.
+	DefaultHistorySize = 10
+)
+
+// ErrSnapshotNotFound is returned by Rollback for a version that is not (or
+// no longer) in the history.
+var ErrSnapshotNotFound = errors.New("configuration snapshot not found")
+
+// WithHistorySize sets how many configuration snapshots the provider keeps
+// for History and Rollback, the current one included (default
+// DefaultHistorySize, minimum 1). The oldest ones are dropped first.
+func WithHistorySize(size int) providerOption {
+	return func(p *provider) {
+		p.historySize = max(size, 1)
+	}
+}
+
+// History returns the kept configuration snapshots, oldest first; the last
+// one is the current configuration.
+func (p *Provider[T]) History() []Snapshot[T] {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	history := make([]Snapshot[T], len(p.history))
+	for i, entry := range p.history {
+		history[i] = entry.Snapshot
+		history[i].Configuration = cloneConfiguration(entry.Configuration)
+	}
+
+	return history
+}
+
+// Rollback restores the configuration of the snapshot version, e.g. to
+// revert a bad live update without restarting. The restored configuration
+// is validated again and stored as a new SnapshotRollback snapshot, even
+// when it equals the current one, so every rollback shows in History; it is
+// delivered to the subscribers and watchers only when it changes the
+// configuration. A later reload or update replaces it as usual.
+func (p *Provider[T]) Rollback(version uint64) error {
+	p.lock.Lock()
+
+	entry, ok := p.snapshotEntry(version)
+	if !ok {
+		p.lock.Unlock()
+		return fmt.Errorf("%w: version %d", ErrSnapshotNotFound, version)
+	}
+
+	old := p.configuration
+	configuration := cloneConfiguration(entry.Configuration)
+	changed, err := p.updateConfiguration(configuration, entry.meta, SnapshotRollback)
+	secretPaths := p.meta.secretPaths
+	p.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	p.log().Info("configuration rolled back", slog.Uint64("version", version))
+
+	if changed {
+		p.notify(Change[T]{Old: old, New: configuration}, secretPaths)
+	}
+
+	return nil
+}
+
+// snapshotEntry returns the history entry of version.
+// Caller MUST hold p.lock.
+func (p *Provider[T]) snapshotEntry(version uint64) (snapshotEntry[T], bool) {
+	for _, entry := range p.history {
+		if entry.Version == version {
+			return entry, true
+		}
+	}
+
+	return snapshotEntry[T]{}, false
+}
+
+// record appends a snapshot of configuration to the history, dropping the
+// oldest ones beyond the history size.
+// Caller MUST hold p.lock write lock.
+func (p *Provider[T]) record(configuration T, meta loadMeta, source SnapshotSource) {
+	p.version++
+	p.history = append(p.history, snapshotEntry[T]{
+		Snapshot: Snapshot[T]{
+			Version:       p.version,
+			Time:          time.Now(),
+			Source:        source,
+			Configuration: cloneConfiguration(configuration),
+		},
+		meta: meta,
+	})
+
+	size := p.historySize
+	if size == 0 {
+		size = DefaultHistorySize
+	}
+
+	if excess := len(p.history) - size; excess > 0 {
+		p.history = append(p.history[:0:0], p.history[excess:]...)
+	}
+}
+
+// cloneConfiguration returns a deep copy of configuration.
+func cloneConfiguration[T any](configuration T) T {
+	var clone T
+
+	reflect.ValueOf(&clone).Elem().Set(cloneValue(reflect.ValueOf(&configuration).Elem()))
+
+	return clone
+}
+
+// cloneValue returns a deep copy of v: pointers, slices, maps and
+// interfaces are copied, as are the exported fields of structs (unexported
+// fields are copied shallowly).
+func cloneValue(v reflect.Value) reflect.Value { //nolint:cyclop
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.New(v.Type().Elem())
+		clone.Elem().Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+
+		return clone
+	case reflect.Array, reflect.Struct:
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+
+		if v.Kind() == reflect.Array {
+			for i := range v.Len() {
+				clone.Index(i).Set(cloneValue(v.Index(i)))
+			}
+
+			return clone
+		}
+
+		for i := range v.NumField() {
+			if clone.Field(i).CanSet() {
+				clone.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+
+		return clone
+	default:
+		return v
+	}
+}
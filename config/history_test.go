@@ -0,0 +1,117 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type historyConfig struct {
+	Name   string            `yaml:"name" validate:"required"`
+	Labels map[string]string `yaml:"labels"`
+	Hosts  []string          `yaml:"hosts"`
+}
+
+func TestProviderHistory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records_and_rolls_back", func(t *testing.T) {
+		t.Parallel()
+
+		provider, profilePath := newLoadProvider(t, "default")
+		_, err := provider.Load(t.Context())
+		require.NoError(t, err)
+
+		require.NoError(t, provider.UpdateConfiguration(loadConfig{Name: "bad"}))
+		writeProfile(t, profilePath, "name: reloaded")
+		_, err = provider.Reload(t.Context())
+		require.NoError(t, err)
+
+		history := provider.History()
+		require.Len(t, history, 3)
+
+		for i, source := range []SnapshotSource{SnapshotLoad, SnapshotUpdate, SnapshotReload} {
+			assert.Equal(t, uint64(i+1), history[i].Version)
+			assert.Equal(t, source, history[i].Source)
+			assert.False(t, history[i].Time.IsZero())
+		}
+
+		assert.Equal(t, "bad", history[1].Configuration.Name)
+
+		changed := make(chan loadConfig, 1)
+		provider.Subscribe(func(_, new loadConfig) { changed <- new })
+
+		require.NoError(t, provider.Rollback(1))
+		assert.Equal(t, "first", provider.MustGet().Name)
+		assert.Equal(t, "first", (<-changed).Name)
+
+		history = provider.History()
+		require.Len(t, history, 4)
+		assert.Equal(t, SnapshotRollback, history[3].Source)
+		assert.Equal(t, uint64(4), history[3].Version)
+
+		require.ErrorIs(t, provider.Rollback(42), ErrSnapshotNotFound)
+	})
+
+	t.Run("rollback_to_current_is_recorded", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewProvider[historyConfig](WithProfilesPath(t.TempDir()))
+		require.NoError(t, provider.UpdateConfiguration(historyConfig{Name: "a"}))
+
+		notified := false
+		provider.Subscribe(func(_, _ historyConfig) { notified = true })
+
+		require.NoError(t, provider.Rollback(1))
+
+		history := provider.History()
+		require.Len(t, history, 2)
+		assert.Equal(t, SnapshotRollback, history[1].Source)
+		assert.Equal(t, "a", history[1].Configuration.Name)
+		assert.False(t, notified, "an unchanged configuration is not delivered")
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewProvider[historyConfig](WithProfilesPath(t.TempDir()), WithHistorySize(2))
+		for _, name := range []string{"a", "b", "c"} {
+			require.NoError(t, provider.UpdateConfiguration(historyConfig{Name: name}))
+		}
+
+		history := provider.History()
+		require.Len(t, history, 2)
+		assert.Equal(t, uint64(2), history[0].Version)
+		assert.Equal(t, "c", history[1].Configuration.Name)
+
+		require.ErrorIs(t, provider.Rollback(1), ErrSnapshotNotFound)
+	})
+
+	t.Run("invalid_updates_are_not_recorded", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewProvider[historyConfig](WithProfilesPath(t.TempDir()))
+		require.NoError(t, provider.UpdateConfiguration(historyConfig{Name: "a"}))
+		require.Error(t, provider.UpdateConfiguration(historyConfig{}))
+		assert.Len(t, provider.History(), 1)
+	})
+
+	t.Run("snapshots_are_copies", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewProvider[historyConfig](WithProfilesPath(t.TempDir()))
+		cfg := historyConfig{Name: "a", Labels: map[string]string{"team": "core"}, Hosts: []string{"h1"}}
+		require.NoError(t, provider.UpdateConfiguration(cfg))
+
+		cfg.Labels["team"] = "changed"
+		cfg.Hosts[0] = "changed"
+
+		snapshot := provider.History()[0]
+		assert.Equal(t, "core", snapshot.Configuration.Labels["team"])
+		assert.Equal(t, "h1", snapshot.Configuration.Hosts[0])
+
+		snapshot.Configuration.Labels["team"] = "mutated"
+		assert.Equal(t, "core", provider.History()[0].Configuration.Labels["team"])
+	})
+}
@@ -0,0 +1,48 @@
+// Package structfield holds the struct field rules shared by the config
+// packages, so the provider, the environment parser and the schema
+// generator agree on which fields are walked and how they are named.
+package structfield
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	urlType             = reflect.TypeFor[url.URL]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// YAMLName returns the yaml key of a struct field as yaml.v3 decodes it,
+// whether the field is inlined, and whether it is skipped (`yaml:"-"`, or
+// unexported and not embedded).
+func YAMLName(field reflect.StructField) (name string, inline bool, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+		return "", false, true
+	}
+
+	name, flags, _ := strings.Cut(tag, ",")
+	for flag := range strings.SplitSeq(flags, ",") {
+		if flag == "inline" {
+			return "", true, false
+		}
+	}
+
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+
+	return name, false, false
+}
+
+// IsScalarStruct reports whether a type is parsed from a single string
+// (time.Time, url.URL, encoding.TextUnmarshaler) instead of being walked as
+// a nested configuration struct.
+func IsScalarStruct(t reflect.Type) bool {
+	return t == timeType || t == urlType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,67 @@
+package structfield_test
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+type (
+	base struct {
+		Zone string
+	}
+
+	config struct {
+		base `yaml:",inline"`
+
+		Name     string
+		Port     int    `yaml:"listen_port,omitempty"`
+		Common   base   `yaml:",omitempty,inline"`
+		Internal string `yaml:"-"`
+		notes    string //nolint:unused // unexported fields are skipped
+	}
+)
+
+func TestYAMLName(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		name   string
+		inline bool
+		skip   bool
+	}
+
+	typ := reflect.TypeFor[config]()
+	tests := map[string]want{
+		"base":     {inline: true},
+		"Name":     {name: "name"},
+		"Port":     {name: "listen_port"},
+		"Common":   {inline: true},
+		"Internal": {skip: true},
+		"notes":    {skip: true},
+	}
+
+	for field, expected := range tests {
+		f, ok := typ.FieldByName(field)
+		assert.True(t, ok, field)
+
+		name, inline, skip := structfield.YAMLName(f)
+		assert.Equal(t, expected, want{name: name, inline: inline, skip: skip}, field)
+	}
+}
+
+func TestIsScalarStruct(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, structfield.IsScalarStruct(reflect.TypeFor[time.Time]()))
+	assert.True(t, structfield.IsScalarStruct(reflect.TypeFor[url.URL]()))
+	assert.True(t, structfield.IsScalarStruct(reflect.TypeFor[net.IP]()), "encoding.TextUnmarshaler")
+	assert.False(t, structfield.IsScalarStruct(reflect.TypeFor[base]()))
+	assert.False(t, structfield.IsScalarStruct(reflect.TypeFor[time.Duration]()))
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// LoadError is returned by Load, Reload and MustGet: the scope and
+	// profiles path that were loaded, and the load and validation errors
+	// (joined; use errors.Is and errors.As to inspect them, e.g. for
+	// profile.ErrProfileNotFound or validation.Errors).
+	LoadError struct {
+		Scope        string
+		ProfilesPath string
+		Err          error
+	}
+)
+
+// WithProfileRequired makes a missing or unreadable profile an error of
+// GetConfiguration and Load instead of a warning, so a misdeployment (a
+// wrong scope or profiles path) fails at startup instead of running on
+// defaults.
+func WithProfileRequired() providerOption {
+	return func(p *provider) {
+		p.profileRequired = true
+	}
+}
+
+// Error returns the scope, the profiles path and the errors.
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("config: loading scope %q from %q: %v", e.Scope, e.ProfilesPath, e.Err)
+}
+
+// Unwrap returns the load and validation errors.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Load returns the current configuration, loading it on the first call
+// like GetConfiguration, but stops when ctx is done (ctx also bounds the
+// secret resolvers) and fails on any load error: source, environment,
+// secret and strict mode errors, and profile errors with
+// WithProfileRequired. A failed load stores nothing, so a later call tries
+// again.
+func (p *Provider[T]) Load(ctx context.Context) (T, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.loaded {
+		return p.configuration, nil
+	}
+
+	configuration, meta, err := p.readConfiguration(ctx, p.profileRequired)
+	if err != nil {
+		return p.configuration, p.loadError(err)
+	}
+
+	if _, err := p.updateConfiguration(configuration, meta, SnapshotLoad); err != nil {
+		return p.configuration, p.loadError(err)
+	}
+
+	if p.logProvenance {
+		p.log().Info("configuration provenance", meta.logAttr())
+	}
+
+	return p.configuration, nil
+}
+
+// Reload reads the configuration again, like a Watch reload: a valid
+// result replaces the current configuration and is delivered to the
+// subscribers and watchers; on error (profile read errors included) the
+// current configuration is kept and returned with the error.
+func (p *Provider[T]) Reload(ctx context.Context) (T, error) {
+	configuration, err := p.reload(ctx)
+	if err != nil {
+		return configuration, p.loadError(err)
+	}
+
+	return configuration, nil
+}
+
+// MustGet returns the configuration, loading it with Load, and panics on
+// error. Meant for startup, where a broken configuration should stop the
+// program.
+func (p *Provider[T]) MustGet() T {
+	configuration, err := p.Load(context.Background())
+	if err != nil {
+		panic(err)
+	}
+
+	return configuration
+}
+
+// current returns the current configuration.
+func (p *Provider[T]) current() T {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.configuration
+}
+
+func (p *provider) loadError(err error) *LoadError {
+	return &LoadError{Scope: p.scope, ProfilesPath: p.profilesPath, Err: err}
+}
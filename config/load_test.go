@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/profile"
+	"github.com/guionardo/go/config/source"
+)
+
+type loadConfig struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+}
+
+type requiredEnvConfig struct {
+	Name  string `yaml:"name" env:"NAME,required"`
+	Token string `yaml:"token" env:"TOKEN,required" safe:"true"`
+}
+
+type loadContextKey struct{}
+
+func newLoadProvider(t *testing.T, scope string, options ...providerOption) (*Provider[loadConfig], string) {
+	t.Helper()
+
+	tmp := t.TempDir()
+	profilePath := path.Join(tmp, "default.yml")
+	require.NoError(t, os.WriteFile(profilePath, []byte("name: first"), 0o600))
+
+	return NewProvider[loadConfig](append([]providerOption{
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope(scope),
+	}, options...)...), profilePath
+}
+
+func TestProviderLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads_once", func(t *testing.T) {
+		t.Parallel()
+
+		provider, profilePath := newLoadProvider(t, "default")
+
+		cfg, err := provider.Load(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "first", cfg.Name)
+
+		writeProfile(t, profilePath, "name: second")
+		cfg, err = provider.Load(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "first", cfg.Name)
+	})
+
+	t.Run("missing_profile_is_a_warning", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := newLoadProvider(t, "production")
+
+		_, err := provider.Load(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("missing_profile_required", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := newLoadProvider(t, "production", WithProfileRequired())
+
+		_, err := provider.Load(t.Context())
+		require.ErrorIs(t, err, profile.ErrProfileNotFound)
+
+		var loadErr *LoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.Equal(t, "production", loadErr.Scope)
+		assert.Contains(t, err.Error(), `loading scope "production"`)
+
+		_, err = provider.GetConfiguration()
+		require.ErrorIs(t, err, profile.ErrProfileNotFound)
+	})
+
+	t.Run("source_error_is_not_stored", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := newLoadProvider(t, "default",
+			WithSources(source.JSON(path.Join(t.TempDir(), "missing.json"))))
+
+		_, err := provider.Load(t.Context())
+		require.Error(t, err)
+
+		provider.lock.RLock()
+		defer provider.lock.RUnlock()
+		assert.False(t, provider.loaded)
+	})
+
+	t.Run("missing_required_env", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewProvider[requiredEnvConfig](WithProfilesPath(t.TempDir()), WithEnvPrefix("LOAD_REQUIRED_"))
+
+		_, err := provider.Load(t.Context())
+		require.ErrorIs(t, err, environment.ErrMissingEnv)
+		assert.Contains(t, err.Error(), "LOAD_REQUIRED_NAME (Name), LOAD_REQUIRED_TOKEN (Token)")
+	})
+
+	t.Run("canceled_context", func(t *testing.T) {
+		t.Parallel()
+
+		provider, _ := newLoadProvider(t, "default")
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := provider.Load(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("context_reaches_secret_resolvers", func(t *testing.T) {
+		t.Parallel()
+
+		provider, profilePath := newLoadProvider(t, "default",
+			WithSecretResolver("ctx", SecretResolverFunc(func(ctx context.Context, reference string) (string, error) {
+				value, ok := ctx.Value(loadContextKey{}).(string)
+				if !ok {
+					return "", errors.New("no context value")
+				}
+
+				return value + "-" + reference, nil
+			})))
+		writeProfile(t, profilePath, "name: first\ntoken: ${ctx:token}")
+
+		cfg, err := provider.Load(context.WithValue(t.Context(), loadContextKey{}, "from-ctx"))
+		require.NoError(t, err)
+		assert.Equal(t, "from-ctx-token", cfg.Token)
+	})
+}
+
+func TestProviderReload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replaces_and_notifies", func(t *testing.T) {
+		t.Parallel()
+
+		provider, profilePath := newLoadProvider(t, "default")
+		_, err := provider.Load(t.Context())
+		require.NoError(t, err)
+
+		changed := make(chan loadConfig, 1)
+		provider.Subscribe(func(_, new loadConfig) { changed <- new })
+
+		writeProfile(t, profilePath, "name: second")
+		cfg, err := provider.Reload(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "second", cfg.Name)
+		assert.Equal(t, "second", (<-changed).Name)
+	})
+
+	t.Run("keeps_current_on_error", func(t *testing.T) {
+		t.Parallel()
+
+		provider, profilePath := newLoadProvider(t, "default")
+		_, err := provider.Load(t.Context())
+		require.NoError(t, err)
+
+		require.NoError(t, os.Remove(profilePath))
+		cfg, err := provider.Reload(t.Context())
+		require.ErrorIs(t, err, profile.ErrProfileNotFound)
+		assert.Equal(t, "first", cfg.Name)
+	})
+}
+
+func TestProviderMustGet(t *testing.T) {
+	t.Parallel()
+
+	provider, _ := newLoadProvider(t, "default")
+	assert.Equal(t, "first", provider.MustGet().Name)
+
+	invalid, _ := newLoadProvider(t, "production", WithProfileRequired())
+	assert.Panics(t, func() { invalid.MustGet() })
+}
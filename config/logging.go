@@ -1,19 +1,38 @@
 package config
 
 import (
+	"errors"
 	"log/slog"
-	"maps"
 	"reflect"
 	"strings"
 	"sync"
+
+	"github.com/guionardo/go/config/validation"
 )
 
+// logger is the default Logger of providers without WithLogger.
 var logger = sync.OnceValue[*slog.Logger](func() *slog.Logger {
 	return slog.With(slog.String("module", "config"))
 })
 
-func getConfigurationLog(c any) slog.Attr {
+// log returns the configured logger, or the default slog logger.
+func (p *provider) log() Logger {
+	if p.logger != nil {
+		return p.logger
+	}
+
+	return logger()
+}
+
+// getConfigurationLog returns the configuration fields as a log group,
+// masking `safe` fields and the fields at or below the masked paths.
+func getConfigurationLog(c any, masked ...string) slog.Attr {
 	configs := getMapFromStruct(c, "")
+	for path := range configs {
+		if isMaskedPath(path, masked) {
+			configs[path] = maskedValue
+		}
+	}
 
 	attrs := make([]any, 0, len(configs))
 
@@ -24,36 +43,92 @@ func getConfigurationLog(c any) slog.Attr {
 	return slog.Group(reflect.TypeOf(c).Name(), attrs...)
 }
 
-// getMapFromStruct returns a map representation, removing the secrets fields for logging
-func getMapFromStruct(c any, parentPath string) map[string]any {
-	if reflect.TypeOf(c).Kind() != reflect.Struct {
-		return map[string]any{
-			parentPath: c,
-		}
+// validationLog returns the log arguments of a validation error: the failed
+// rules by field path, without their messages (which may quote the value),
+// and the value masked for `safe` fields and the masked paths; other
+// errors (e.g. from a Validator method) are logged as is.
+func validationLog(c any, err error, masked []string) []any {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
 	}
 
-	attrs := map[string]any{}
-
-	for i := 0; i < reflect.TypeOf(c).NumField(); i++ {
-		field := reflect.TypeOf(c).Field(i)
+	var (
+		fields []any
+		others []error
+	)
 
-		if _, safe := field.Tag.Lookup("safe"); safe {
-			attrs[fieldPath(parentPath, field.Name)] = "********"
+	for _, err := range errs {
+		var fieldErrs validation.Errors
+		if !errors.As(err, &fieldErrs) {
+			others = append(others, err)
 			continue
 		}
 
-		if field.Type.Kind() == reflect.Struct {
-			subStruct := getMapFromStruct(reflect.ValueOf(c).Field(i).Interface(), fieldPath(parentPath, field.Name))
-			maps.Copy(attrs, subStruct)
+		for _, fieldErr := range fieldErrs {
+			value := fieldErr.Value
+			if isMaskedPath(fieldErr.Path, masked) || isSafePath(c, fieldErr.Path) {
+				value = maskedValue
+			}
 
-			continue
+			fields = append(fields, slog.Group(fieldErr.Path,
+				slog.String("tag", fieldErr.Tag),
+				slog.String("param", fieldErr.Param),
+				slog.Any("value", value)))
 		}
+	}
 
-		attrs[fieldPath(parentPath, field.Name)] = reflect.ValueOf(c).Field(i).Interface()
+	var args []any
+	if len(fields) > 0 {
+		args = append(args, slog.Group("fields", fields...))
 	}
 
+	if len(others) > 0 {
+		args = append(args, slog.Any("error", errors.Join(others...)))
+	}
+
+	return args
+}
+
+// isSafePath reports whether path is a `safe` field of c or below one.
+func isSafePath(c any, path string) bool {
+	safe := false
+
+	walkFields(reflect.ValueOf(c), "", func(fieldPath string, _ any, isSafe bool) {
+		if isSafe && isMaskedPath(path, []string{fieldPath}) {
+			safe = true
+		}
+	})
+
+	return safe
+}
+
+// getMapFromStruct returns a map representation, removing the secrets fields for logging
+func getMapFromStruct(c any, parentPath string) map[string]any {
+	attrs := map[string]any{}
+
+	walkFields(reflect.ValueOf(c), parentPath, func(path string, value any, safe bool) {
+		if safe {
+			value = maskedValue
+		}
+
+		attrs[path] = value
+	})
+
 	return attrs
 }
+
 func fieldPath(fields ...string) string {
 	return strings.TrimPrefix(strings.Join(fields, "."), ".")
 }
+
+// isMaskedPath reports whether path is one of masked or below one of them.
+func isMaskedPath(path string, masked []string) bool {
+	for _, m := range masked {
+		if path == m || strings.HasPrefix(path, m+".") {
+			return true
+		}
+	}
+
+	return false
+}
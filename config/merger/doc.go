@@ -6,4 +6,14 @@
 // Usage:
 //
 //	merged := merger.MergeMaps(base, override)
+//
+// Merge makes the behaviour configurable and reports type conflicts (a
+// value whose type differs from the value it would override, e.g. a
+// string over a map) instead of skipping them silently:
+//
+//	merged, conflicts := merger.Merge([]map[string]any{base, override},
+//	    merger.WithListStrategy(merger.ListAppend, "tags"),
+//	    merger.WithListStrategy(merger.ListMergeByKey("name"), "servers"),
+//	    merger.WithNullDelete(),                          // `key: null` removes key
+//	    merger.WithConflictPolicy(merger.ConflictOverride)) // later value wins
 package merger
@@ -1,41 +1,310 @@
 package merger
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// Conflict is a value whose type differs from the value it would
+	// override. Unless ConflictOverride is set the earlier value is kept.
+	Conflict struct {
+		// Path is the key path of the value, e.g. ["server", "port"]
+		// (list items are addressed by their index in the merged list).
+		Path []string
+		// Layer is the index of the map holding Value.
+		Layer int
+		// Current is the earlier value.
+		Current any
+		// Value is the later value.
+		Value any
+		// Overridden reports whether Value replaced Current.
+		Overridden bool
+	}
+
+	// Origins maps the dotted key path of every leaf of a merged map (a
+	// value other than a non-empty map; lists are leaves) to the index of
+	// the map that set it last.
+	Origins map[string]int
+
+	// merge accumulates the conflicts (and, when tracked, the origins) of a
+	// Merge run.
+	merge struct {
+		options
+		layer     int
+		conflicts []Conflict
+		origins   Origins
+	}
+)
 
 // MergeMaps performs a recursive deep-merge of multiple maps.
 // Each subsequent map's values are merged into the accumulator. Nested maps
 // are merged recursively; non-map values from later maps overwrite earlier ones.
+// Values whose type differs from the value they would override are skipped
+// (see Merge for the configurable behaviour).
 func MergeMaps(maps ...map[string]any) map[string]any {
+	merged, _ := Merge(maps)
+
+	return merged
+}
+
+// MergeMapsWithConflicts is Merge without options: it merges maps like
+// MergeMaps and also returns the values that were skipped because of a type
+// mismatch, in merge order.
+func MergeMapsWithConflicts(maps ...map[string]any) (map[string]any, []Conflict) {
+	return Merge(maps)
+}
+
+// Merge deep-merges maps in order, later maps winning, and returns the type
+// conflicts met on the way. Without options it behaves like MergeMaps:
+//   - nested maps are merged recursively; map[any]any maps (as produced by
+//     some YAML decoders) are converted to map[string]any
+//   - lists are replaced (see WithListStrategy)
+//   - a value whose type differs from the value it would override is
+//     skipped and reported (see WithConflictPolicy); integers and floats
+//     are compatible
+//   - a nil value is a type conflict (see WithNullDelete)
+//
+// The input maps are not modified and the result shares no map or list
+// with them.
+func Merge(maps []map[string]any, opts ...Option) (map[string]any, []Conflict) {
+	m := &merge{options: newOptions(opts)}
+
+	return m.run(maps), m.conflicts
+}
+
+// MergeWithOrigins merges maps like Merge and also returns the map each
+// leaf of the result comes from: deleted keys (WithNullDelete) and skipped
+// conflicting values are not credited to their map.
+func MergeWithOrigins(maps []map[string]any, opts ...Option) (map[string]any, []Conflict, Origins) {
+	m := &merge{options: newOptions(opts), origins: Origins{}}
+
+	return m.run(maps), m.conflicts, m.origins
+}
+
+// run merges maps in order.
+func (m *merge) run(maps []map[string]any) map[string]any {
 	current := make(map[string]any)
-	for _, m := range maps {
-		updateMapValues(current, m)
+
+	for i, from := range maps {
+		m.layer = i
+		m.update(current, from, nil, nil)
 	}
 
 	return current
 }
 
-// updateMapValues updates the values of a map with the values of another map.
-func updateMapValues(current, from map[string]any) {
+// String formats the conflict as "path: type value skipped, keeps type value"
+// or "path: type value overrides type value".
+func (c Conflict) String() string {
+	path := strings.Join(c.Path, ".")
+	if c.Overridden {
+		return fmt.Sprintf("%s: %T %v overrides %T %v", path, c.Value, c.Value, c.Current, c.Current)
+	}
+
+	return fmt.Sprintf("%s: %T %v skipped, keeps %T %v", path, c.Value, c.Value, c.Current, c.Current)
+}
+
+// update merges from into current. path is the key path of current, with
+// list indexes; pattern is the same path without them, for the list
+// strategy lookup.
+func (m *merge) update(current, from map[string]any, path, pattern []string) {
 	if current == nil {
 		current = make(map[string]any)
 	}
 
 	for k, v := range from {
+		v = normalize(v)
+		keyPath := appendPath(path, k)
+
 		currentValue, ok := current[k]
-		if !ok {
+		switch {
+		case v == nil && m.nullDelete:
+			delete(current, k)
+			m.forget(keyPath)
+		case !ok:
 			current[k] = v
-			continue
+			m.credit(keyPath, v)
+		case !compatible(currentValue, v):
+			overridden := m.conflictPolicy == ConflictOverride
+			m.conflicts = append(m.conflicts, Conflict{
+				Path:       keyPath,
+				Layer:      m.layer,
+				Current:    currentValue,
+				Value:      v,
+				Overridden: overridden,
+			})
+
+			if overridden {
+				current[k] = v
+				m.forget(keyPath)
+				m.credit(keyPath, v)
+			}
+		default:
+			if child, isMap := v.(map[string]any); isMap {
+				if len(child) > 0 && m.origins != nil {
+					delete(m.origins, strings.Join(keyPath, ".")) // no longer an empty map leaf
+				}
+
+				current[k] = m.value(currentValue, v, keyPath, appendPath(pattern, k))
+
+				continue
+			}
+
+			current[k] = m.value(currentValue, v, keyPath, appendPath(pattern, k))
+			m.forget(keyPath)
+			m.credit(keyPath, v)
+		}
+	}
+}
+
+// credit records the current layer as the origin of the leaves of v, set
+// at path.
+func (m *merge) credit(path []string, v any) {
+	if m.origins == nil {
+		return
+	}
+
+	if child, ok := v.(map[string]any); ok && len(child) > 0 {
+		for k, item := range child {
+			m.credit(appendPath(path, k), item)
+		}
+
+		return
+	}
+
+	m.origins[strings.Join(path, ".")] = m.layer
+}
+
+// forget removes the origins of the value at path and below it.
+func (m *merge) forget(path []string) {
+	if m.origins == nil {
+		return
+	}
+
+	key := strings.Join(path, ".")
+	for origin := range m.origins {
+		if origin == key || strings.HasPrefix(origin, key+".") {
+			delete(m.origins, origin)
 		}
+	}
+}
+
+// value returns the merge of two compatible values.
+func (m *merge) value(current, from any, path, pattern []string) any {
+	switch from := from.(type) {
+	case map[string]any:
+		m.update(current.(map[string]any), from, path, pattern) //nolint:forcetypeassert
+
+		return current
+	case []any:
+		return m.list(current.([]any), from, path, pattern) //nolint:forcetypeassert
+	default:
+		return from
+	}
+}
+
+// list merges two lists with the strategy of their path.
+func (m *merge) list(current, from []any, path, pattern []string) []any {
+	strategy := m.listStrategy(pattern)
 
-		if reflect.TypeOf(currentValue) != reflect.TypeOf(v) {
-			continue
+	switch strategy.kind {
+	case listAppend:
+		return append(current, from...)
+	case listMergeByKey:
+		for _, item := range from {
+			index := keyIndex(current, item, strategy.key)
+			if index < 0 {
+				current = append(current, item)
+				continue
+			}
+
+			m.update(current[index].(map[string]any), item.(map[string]any), //nolint:forcetypeassert
+				appendPath(path, fmt.Sprint(index)), pattern)
 		}
 
-		if reflect.ValueOf(currentValue).Kind() == reflect.Map {
-			updateMapValues(currentValue.(map[string]any), v.(map[string]any))
-			continue
+		return current
+	default:
+		return from
+	}
+}
+
+// keyIndex returns the index of the map of items with the same key value
+// as item, or -1 when item is not a map holding key or no item matches.
+func keyIndex(items []any, item any, key string) int {
+	itemMap, ok := item.(map[string]any)
+	if !ok {
+		return -1
+	}
+
+	value, ok := itemMap[key]
+	if !ok {
+		return -1
+	}
+
+	for i, candidate := range items {
+		if candidateMap, ok := candidate.(map[string]any); ok {
+			if candidateValue, ok := candidateMap[key]; ok && fmt.Sprint(candidateValue) == fmt.Sprint(value) {
+				return i
+			}
 		}
+	}
 
-		current[k] = v
+	return -1
+}
+
+// compatible reports whether from can be merged over current: same type,
+// or both numbers.
+func compatible(current, from any) bool {
+	if reflect.TypeOf(current) == reflect.TypeOf(from) {
+		return true
+	}
+
+	return isNumber(current) && isNumber(from)
+}
+
+func isNumber(v any) bool {
+	switch reflect.ValueOf(v).Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalize returns a copy of maps (with string keys) and lists (as []any),
+// recursively, so the merge never modifies its inputs. Other values are
+// returned as is.
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Map:
+		m := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			m[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value().Interface())
+		}
+
+		return m
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = normalize(rv.Index(i).Interface())
+		}
+
+		return items
+	default:
+		return v
 	}
 }
+
+func appendPath(path []string, key string) []string {
+	return append(path[:len(path):len(path)], key)
+}
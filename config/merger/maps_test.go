@@ -40,3 +40,162 @@ func TestMergeMaps(t *testing.T) {
 	got := merger.MergeMaps(m1, m2, m3)
 	require.Equal(t, want, got)
 }
+
+func TestMergeMapsWithConflicts(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]any{
+		"server": map[string]any{"port": 8080, "host": "localhost"},
+		"ratio":  0.5,
+	}
+	override := map[string]any{
+		"server": map[string]any{"port": "http"},
+		"ratio":  1,
+		"name":   "app",
+	}
+
+	got, conflicts := merger.MergeMapsWithConflicts(base, override)
+	require.Equal(t, map[string]any{
+		"server": map[string]any{"port": 8080, "host": "localhost"},
+		"ratio":  1,
+		"name":   "app",
+	}, got, "integers and floats are compatible")
+
+	require.Equal(t, []merger.Conflict{
+		{Path: []string{"server", "port"}, Layer: 1, Current: 8080, Value: "http"},
+	}, conflicts)
+	require.Equal(t, "server.port: string http skipped, keeps int 8080", conflicts[0].String())
+
+	_, conflicts = merger.MergeMapsWithConflicts(base, map[string]any{"ratio": 0.7})
+	require.Empty(t, conflicts)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	base := map[string]any{
+		"servers": []any{
+			map[string]any{"name": "a", "port": 1},
+			map[string]any{"name": "b", "port": 2, "tags": []any{"x"}},
+		},
+		"tags":  []any{"base"},
+		"debug": true,
+		"db":    map[string]any{"host": "localhost"},
+	}
+	override := map[string]any{
+		"servers": []any{
+			map[string]any{"name": "b", "port": 3, "tags": []any{"y"}},
+			map[string]any{"name": "c"},
+		},
+		"tags":  []string{"override"},
+		"debug": nil,
+		"db":    "postgres://db",
+	}
+
+	t.Run("defaults_replace_lists_and_keep_conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		got, conflicts := merger.Merge([]map[string]any{base, override})
+		require.Equal(t, override["servers"], got["servers"])
+		require.Equal(t, []any{"override"}, got["tags"])
+		require.Equal(t, true, got["debug"])
+		require.Equal(t, map[string]any{"host": "localhost"}, got["db"])
+		require.Len(t, conflicts, 2)
+	})
+
+	t.Run("list_strategies_by_path", func(t *testing.T) {
+		t.Parallel()
+
+		got, _ := merger.Merge([]map[string]any{base, override},
+			merger.WithListStrategy(merger.ListAppend),
+			merger.WithListStrategy(merger.ListMergeByKey("name"), "servers"))
+		require.Equal(t, []any{
+			map[string]any{"name": "a", "port": 1},
+			map[string]any{"name": "b", "port": 3, "tags": []any{"x", "y"}},
+			map[string]any{"name": "c"},
+		}, got["servers"])
+		require.Equal(t, []any{"base", "override"}, got["tags"])
+
+		got, _ = merger.Merge([]map[string]any{base, override},
+			merger.WithListStrategy(merger.ListMergeByKey("name"), "servers"),
+			merger.WithListStrategy(merger.ListReplace, "servers.tags"))
+		require.Equal(t, []any{"y"}, got["servers"].([]any)[1].(map[string]any)["tags"])
+	})
+
+	t.Run("null_deletes_and_override_policy", func(t *testing.T) {
+		t.Parallel()
+
+		got, conflicts := merger.Merge([]map[string]any{base, override},
+			merger.WithNullDelete(), merger.WithConflictPolicy(merger.ConflictOverride))
+		require.NotContains(t, got, "debug")
+		require.Equal(t, "postgres://db", got["db"])
+		require.Equal(t, []merger.Conflict{{
+			Path: []string{"db"}, Layer: 1, Current: map[string]any{"host": "localhost"},
+			Value: "postgres://db", Overridden: true,
+		}}, conflicts)
+		require.Equal(t, "db: string postgres://db overrides map[string]interface {} map[host:localhost]",
+			conflicts[0].String())
+	})
+
+	t.Run("any_key_maps", func(t *testing.T) {
+		t.Parallel()
+
+		got, conflicts := merger.Merge([]map[string]any{
+			{"db": map[string]any{"host": "localhost", "port": 5432}},
+			{"db": map[any]any{"host": "db", 1: "one"}},
+		})
+		require.Empty(t, conflicts)
+		require.Equal(t, map[string]any{"db": map[string]any{"host": "db", "port": 5432, "1": "one"}}, got)
+	})
+
+	t.Run("inputs_are_not_modified", func(t *testing.T) {
+		t.Parallel()
+
+		first := map[string]any{"db": map[string]any{"host": "localhost"}}
+		_, _ = merger.Merge([]map[string]any{first, {"db": map[string]any{"host": "db"}}})
+		require.Equal(t, map[string]any{"db": map[string]any{"host": "localhost"}}, first)
+	})
+}
+
+func TestMergeWithOrigins(t *testing.T) {
+	t.Parallel()
+
+	maps := []map[string]any{
+		{"name": "base", "port": 8080, "db": map[string]any{"host": "localhost", "pool": 1}, "tags": []any{"a"}},
+		{"port": "http", "db": map[string]any{"pool": 5}, "debug": true, "empty": map[string]any{}},
+		{"debug": nil, "tags": []any{"b"}, "empty": map[string]any{"key": "value"}},
+	}
+
+	t.Run("winning_layers", func(t *testing.T) {
+		t.Parallel()
+
+		merged, conflicts, origins := merger.MergeWithOrigins(maps, merger.WithNullDelete())
+		require.Len(t, conflicts, 1)
+		require.NotContains(t, merged, "debug")
+		require.Equal(t, merger.Origins{
+			"name":      0,
+			"port":      0, // the conflicting "http" was skipped
+			"db.host":   0,
+			"db.pool":   1,
+			"tags":      2,
+			"empty.key": 2,
+		}, origins, "deleted keys have no origin")
+	})
+
+	t.Run("conflict_override", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, origins := merger.MergeWithOrigins(maps[:2], merger.WithConflictPolicy(merger.ConflictOverride))
+		require.Equal(t, 1, origins["port"])
+	})
+
+	t.Run("map_replaced_by_value", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, origins := merger.MergeWithOrigins([]map[string]any{
+			{"db": map[string]any{"host": "localhost"}},
+			{"db": "postgres://db"},
+		}, merger.WithConflictPolicy(merger.ConflictOverride))
+		require.Equal(t, merger.Origins{"db": 1}, origins)
+	})
+}
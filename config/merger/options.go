@@ -0,0 +1,107 @@
+package merger
+
+import "strings"
+
+type (
+	// Option configures Merge.
+	Option func(*options)
+
+	// ListStrategy is how a list overrides an earlier list.
+	ListStrategy struct {
+		kind listKind
+		key  string
+	}
+
+	// ConflictPolicy is how Merge resolves a value whose type differs from
+	// the value it would override.
+	ConflictPolicy uint8
+
+	listKind uint8
+
+	options struct {
+		listDefault    ListStrategy
+		lists          map[string]ListStrategy
+		nullDelete     bool
+		conflictPolicy ConflictPolicy
+	}
+)
+
+const (
+	listReplace listKind = iota
+	listAppend
+	listMergeByKey
+)
+
+const (
+	// ConflictKeep keeps the earlier value (the default).
+	ConflictKeep ConflictPolicy = iota
+	// ConflictOverride replaces the earlier value, like any other override.
+	ConflictOverride
+)
+
+var (
+	// ListReplace replaces the earlier list (the default).
+	ListReplace = ListStrategy{kind: listReplace}
+	// ListAppend appends the items to the earlier list.
+	ListAppend = ListStrategy{kind: listAppend}
+)
+
+// ListMergeByKey deep-merges list items that are maps holding the same
+// value under key (e.g. "name"); other items are appended.
+func ListMergeByKey(key string) ListStrategy {
+	return ListStrategy{kind: listMergeByKey, key: key}
+}
+
+// WithListStrategy sets how lists are merged at the given dotted key paths
+// (e.g. "servers", "servers.tags" for a list inside list items), or for all
+// lists when no path is given.
+func WithListStrategy(strategy ListStrategy, paths ...string) Option {
+	return func(o *options) {
+		if len(paths) == 0 {
+			o.listDefault = strategy
+			return
+		}
+
+		if o.lists == nil {
+			o.lists = make(map[string]ListStrategy, len(paths))
+		}
+
+		for _, path := range paths {
+			o.lists[path] = strategy
+		}
+	}
+}
+
+// WithNullDelete makes an explicit null (nil) value delete the key from
+// the merged map instead of being reported as a type conflict.
+func WithNullDelete() Option {
+	return func(o *options) {
+		o.nullDelete = true
+	}
+}
+
+// WithConflictPolicy sets how type conflicts are resolved. Conflicts are
+// returned by Merge with either policy.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(o *options) {
+		o.conflictPolicy = policy
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// listStrategy returns the strategy of the list at the key path pattern.
+func (o options) listStrategy(pattern []string) ListStrategy {
+	if strategy, ok := o.lists[strings.Join(pattern, ".")]; ok {
+		return strategy
+	}
+
+	return o.listDefault
+}
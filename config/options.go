@@ -23,7 +23,7 @@ func (p *provider) postInit() *provider {
 		p.profilesPath = environment.GetEnv(EnvConfigurationLog, DefaultConfigurationPath)
 	}
 
-	logger().Info("configuration provider initialized",
+	p.log().Info("configuration provider initialized",
 		slog.String("defaultScope", p.defaultScope),
 		slog.String("scope", p.scope),
 		slog.String("profilesPath", p.profilesPath))
@@ -64,6 +64,25 @@ func WithDebugLogger() providerOption {
 	}
 }
 
+// WithEnvPrefix prepends prefix to every environment variable read into the
+// configuration, explicit `env` tags included (e.g. "MYAPP_").
+func WithEnvPrefix(prefix string) providerOption {
+	return func(p *provider) {
+		p.envPrefix = prefix
+		p.envOptions = append(p.envOptions, environment.WithPrefix(prefix))
+	}
+}
+
+// WithEnvAutoNaming derives environment variable names for fields without an
+// `env` tag from their path (Database.Pool.Size reads DATABASE_POOL_SIZE).
+// Nested structs can override their segment with the `envPrefix` tag and
+// fields opt out with `env:"-"`.
+func WithEnvAutoNaming() providerOption {
+	return func(p *provider) {
+		p.envOptions = append(p.envOptions, environment.WithAutoNaming())
+	}
+}
+
 // WithScope sets the configuration scope name used for profile selection.
 // Scope is used to pick a scope-specific YAML file (e.g., "production", "development").
 func WithScope(scope string) providerOption {
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+// goFieldPath converts a yaml key path (e.g. ["database", "password"]) to
+// the dotted Go field path used by logs and diffs ("Database.Password").
+// The path stops at the first non-struct field, so an element of a slice or
+// map maps to the whole field. It returns "" when the path matches no field.
+func goFieldPath(t reflect.Type, yamlPath []string) string {
+	var fields []string
+
+	for _, key := range yamlPath {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			break
+		}
+
+		field, ok := yamlField(t, key)
+		if !ok {
+			return ""
+		}
+
+		fields = append(fields, field.Name)
+		t = field.Type
+	}
+
+	return fieldPath(fields...)
+}
+
+// isSafeYAMLPath reports whether a yaml key path of t is, or is inside, a
+// `safe`-tagged field.
+func isSafeYAMLPath(t reflect.Type, yamlPath []string) bool {
+	for _, key := range yamlPath {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+
+		field, ok := yamlField(t, key)
+		if !ok {
+			return false
+		}
+
+		if _, safe := field.Tag.Lookup("safe"); safe {
+			return true
+		}
+
+		t = field.Type
+	}
+
+	return false
+}
+
+// yamlField finds the field decoded from key, looking into inlined structs.
+// For inlined fields the returned Name is prefixed with the inlining
+// field name, as walkFields reports them ("Base.Name").
+func yamlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, inline, skip := structfield.YAMLName(field)
+		switch {
+		case skip:
+			continue
+		case inline && field.Type.Kind() == reflect.Struct:
+			if inner, ok := yamlField(field.Type, key); ok {
+				inner.Name = field.Name + "." + inner.Name
+				return inner, true
+			}
+		case name == key:
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// secretFieldPaths converts the yaml paths of resolved secrets to sorted,
+// unique field paths of t.
+func secretFieldPaths(t reflect.Type, yamlPaths [][]string) []string {
+	var paths []string
+
+	for _, yamlPath := range yamlPaths {
+		if path := goFieldPath(t, yamlPath); path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	slices.Sort(paths)
+
+	return paths
+}
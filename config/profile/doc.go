@@ -5,7 +5,23 @@
 // with the active scope. Supports .yml, .yaml, .YML, .YAML extensions.
 // Includes path traversal protection.
 //
+// Encrypted profiles ("production.yaml.enc", see config/crypt) are found
+// after the plain ones and decrypted with the WithDecrypter key.
+//
+// Scopes may be comma-separated lists, merged in order ("production,staging").
+// A profile may extend other scopes and include shared fragments (paths
+// relative to the profile directory); both are merged before the profile
+// itself, each file once, and cycles are reported as ErrProfileCycle:
+//
+//	# staging.yml
+//	extends: production          # production.yml may extend default
+//	include: [shared/logging]
+//	database:
+//	  host: staging-db
+//
 // Usage:
 //
 //	data, err := profile.GetScopedProfileContent("/etc/app", "default", "production")
+//
+// WithLogger receives a "profile found" event for every loaded file.
 package profile
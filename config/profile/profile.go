@@ -1,20 +1,22 @@
 package profile
 
 import (
+	"bytes"
 	"fmt"
-	"log/slog"
 	"os"
 	"path"
 	"strings"
 
-	"github.com/guionardo/go/config/merger"
-
 	"gopkg.in/yaml.v3"
+
+	"github.com/guionardo/go/config/crypt"
 )
 
-// GetScopedProfileContent tries to find the scope files, unmarshal and merge the content into a new YAML representation
-func GetScopedProfileContent(basePath, defaultScope, scope string) ([]byte, error) {
-	merged, err := getProfileMap(basePath, defaultScope, scope)
+// GetScopedProfileContent tries to find the scope files, unmarshal and merge the content into a new YAML representation.
+// Scopes may be comma-separated lists and profiles may extend other scopes
+// (ExtendsKey) and include fragments (IncludeKey).
+func GetScopedProfileContent(basePath, defaultScope, scope string, opts ...Option) ([]byte, error) {
+	merged, err := getProfileMap(basePath, defaultScope, scope, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -22,78 +24,122 @@ func GetScopedProfileContent(basePath, defaultScope, scope string) ([]byte, erro
 	return yaml.Marshal(merged)
 }
 
-func getProfileMap(basePath, defaultScope, scope string) (map[string]any, error) {
-	defaultProfile, scopeProfile, err := getProfileFiles(basePath, defaultScope, scope)
-	if err != nil {
-		return nil, err
-	}
+// GetScopedProfileMap finds the scope files and returns their merged content
+// (the scope profile over the default one).
+func GetScopedProfileMap(basePath, defaultScope, scope string, opts ...Option) (map[string]any, error) {
+	return getProfileMap(basePath, defaultScope, scope, opts)
+}
 
-	defaultMap, err := readProfileMap(defaultProfile)
+// GetScopedProfileLayers finds the scope files and returns them unmerged,
+// in merge order (inherited scopes and includes first), with the line of
+// each key.
+func GetScopedProfileLayers(basePath, defaultScope, scope string, opts ...Option) ([]Layer, error) {
+	r, err := resolveScopes(basePath, defaultScope, scope, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	scopeMap, err := readProfileMap(scopeProfile)
+	return r.layers, nil
+}
+
+// getProfileMap merges the profiles of the default scope and then of the
+// scope, each of which may be a comma-separated list, following their
+// extends and include directives.
+func getProfileMap(basePath, defaultScope, scope string, opts []Option) (map[string]any, error) {
+	r, err := resolveScopes(basePath, defaultScope, scope, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	merged := merger.MergeMaps(defaultMap, scopeMap)
-
-	return merged, nil
+	return r.merged(), nil
 }
 
-func readProfileMap(profile string) (map[string]any, error) {
-	file, err := os.Open(path.Clean(profile))
-	if err != nil {
-		return nil, fmt.Errorf("error reading profile %s - %w", profile, err)
+func resolveScopes(basePath, defaultScope, scope string, opts []Option) (*resolver, error) {
+	scopes := append(SplitScopes(defaultScope), SplitScopes(scope)...)
+	for _, s := range scopes {
+		if err := checkBasePath(basePath, s); err != nil {
+			return nil, err
+		}
 	}
-	defer file.Close() //nolint: errcheck
 
-	pm := make(map[string]any)
+	r := newResolver(basePath, opts)
+	for _, s := range scopes {
+		if err := r.scope(s); err != nil {
+			return nil, err
+		}
+	}
 
-	err = yaml.NewDecoder(file).Decode(&pm)
+	return r, nil
+}
+
+// readProfile reads a profile file with the line of each key, decrypting
+// it when its name ends with crypt.Extension.
+func readProfile(profile string, decrypter crypt.Decrypter) (Layer, error) {
+	content, err := os.ReadFile(path.Clean(profile))
 	if err != nil {
-		return nil, fmt.Errorf("error decoding profile %s - %w", profile, err)
+		return Layer{}, fmt.Errorf("error reading profile %s - %w", profile, err)
 	}
 
-	return pm, nil
-}
+	encrypted := strings.HasSuffix(profile, crypt.Extension)
+	if encrypted {
+		if decrypter == nil {
+			return Layer{}, fmt.Errorf("error decrypting profile %s - %w", profile, crypt.ErrNoKey)
+		}
 
-func getProfileFiles(
-	basePath string,
-	defaultScope string,
-	scope string,
-) (defaultProfile, scopeProfile string, err error) {
-	for _, s := range []string{defaultScope, scope} {
-		clean := path.Clean(path.Join(basePath, s))
-		if !strings.HasPrefix(clean, path.Clean(basePath)) {
-			return "", "", fmt.Errorf("scope %q escapes base path %q", s, basePath)
+		if content, err = decrypter.Decrypt(content); err != nil {
+			return Layer{}, fmt.Errorf("error decrypting profile %s - %w", profile, err)
 		}
 	}
 
-	defaultProfile, err = findYAMLFile(path.Join(basePath, defaultScope))
-	if err != nil {
-		return "", "", err
+	var node yaml.Node
+
+	pm := make(map[string]any)
+
+	err = yaml.NewDecoder(bytes.NewReader(content)).Decode(&node)
+	if err == nil {
+		err = node.Decode(&pm)
 	}
 
-	scopeProfile, err = findYAMLFile(path.Join(basePath, scope))
 	if err != nil {
-		return "", "", err
+		return Layer{}, fmt.Errorf("error decoding profile %s - %w", profile, err)
 	}
 
-	slog.Debug("getProfileFiles", slog.String("default", defaultProfile), slog.String("scope", scopeProfile))
+	lines := make(map[string]int)
+	collectLines(&node, "", lines)
+
+	return Layer{File: profile, Values: pm, Lines: lines, Encrypted: encrypted}, nil
+}
 
-	return defaultProfile, scopeProfile, nil
+// collectLines maps the dotted key path of every mapping key to its line.
+func collectLines(node *yaml.Node, parent string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collectLines(child, parent, lines)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if parent != "" {
+				key = parent + "." + key
+			}
+
+			lines[key] = node.Content[i].Line
+			collectLines(node.Content[i+1], key, lines)
+		}
+	}
 }
 
+// findYAMLFile returns the first existing file of fileName with a YAML
+// extension, plain files before encrypted ones ("production.yaml.enc").
 func findYAMLFile(fileName string) (string, error) {
-	for _, ext := range []string{"", ".yml", ".yaml", ".YML", ".YAML"} {
-		if stat, err := os.Stat(fileName + ext); err == nil && !stat.IsDir() {
-			slog.Debug("findYAMLFile", slog.String("filename", fileName+ext))
-			return fileName + ext, nil
+	for _, suffix := range []string{"", crypt.Extension} {
+		for _, ext := range []string{"", ".yml", ".yaml", ".YML", ".YAML"} {
+			if stat, err := os.Stat(fileName + ext + suffix); err == nil && !stat.IsDir() {
+				return fileName + ext + suffix, nil
+			}
 		}
 	}
 
-	return "", fmt.Errorf("file not found: %s", fileName)
+	return "", fmt.Errorf("%w: %s", ErrProfileNotFound, fileName)
 }
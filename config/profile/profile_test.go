@@ -30,7 +30,7 @@ func Test_findYAMLFile(t *testing.T) {
 	})
 }
 
-func Test_getProfileFiles(t *testing.T) {
+func TestGetScopedProfileLayers_Files(t *testing.T) {
 	t.Parallel()
 
 	t.Run("two_existing_files", func(t *testing.T) {
@@ -39,15 +39,16 @@ func Test_getProfileFiles(t *testing.T) {
 		tmp := t.TempDir()
 
 		defaultScope := path.Join(tmp, "default.yml")
-		require.NoError(t, os.WriteFile(defaultScope, []byte{}, 0600))
+		require.NoError(t, os.WriteFile(defaultScope, []byte("name: x"), 0600))
 
 		scope := path.Join(tmp, "scope.yaml")
-		require.NoError(t, os.WriteFile(scope, []byte{}, 0600))
+		require.NoError(t, os.WriteFile(scope, []byte("name: x"), 0600))
 
-		ds, sc, err := getProfileFiles(tmp, "default", "scope")
+		layers, err := GetScopedProfileLayers(tmp, "default", "scope")
 		require.NoError(t, err)
-		require.Equal(t, defaultScope, ds)
-		require.Equal(t, scope, sc)
+		require.Len(t, layers, 2)
+		require.Equal(t, defaultScope, layers[0].File)
+		require.Equal(t, scope, layers[1].File)
 	})
 
 	t.Run("not_existing_default_profile", func(t *testing.T) {
@@ -56,10 +57,10 @@ func Test_getProfileFiles(t *testing.T) {
 		tmp := t.TempDir()
 
 		scope := path.Join(tmp, "scope.yaml")
-		require.NoError(t, os.WriteFile(scope, []byte{}, 0600))
+		require.NoError(t, os.WriteFile(scope, []byte("name: x"), 0600))
 
-		_, _, err := getProfileFiles(tmp, "default", "scope")
-		require.Error(t, err)
+		_, err := GetScopedProfileLayers(tmp, "default", "scope")
+		require.ErrorIs(t, err, ErrProfileNotFound)
 	})
 
 	t.Run("not_existing_profile", func(t *testing.T) {
@@ -68,10 +69,10 @@ func Test_getProfileFiles(t *testing.T) {
 		tmp := t.TempDir()
 
 		defaultScope := path.Join(tmp, "default.yml")
-		require.NoError(t, os.WriteFile(defaultScope, []byte{}, 0600))
+		require.NoError(t, os.WriteFile(defaultScope, []byte("name: x"), 0600))
 
-		_, _, err := getProfileFiles(tmp, "default", "scope")
-		require.Error(t, err)
+		_, err := GetScopedProfileLayers(tmp, "default", "scope")
+		require.ErrorIs(t, err, ErrProfileNotFound)
 	})
 }
 
@@ -101,37 +102,35 @@ func TestGetScopedProfileContent_InvalidYAML(t *testing.T) {
 	})
 }
 
-func TestGetProfileFiles_PathTraversal(t *testing.T) {
+func TestGetScopedProfileLayers_PathTraversal(t *testing.T) {
 	t.Parallel()
 
 	tmp := t.TempDir()
 
 	t.Run("simple_parent_traversal", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := getProfileFiles(tmp, "../etc", "default")
+		_, err := GetScopedProfileLayers(tmp, "../etc", "default")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "escapes base path")
 	})
 
 	t.Run("deep_traversal", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := getProfileFiles(tmp, "../../../etc", "default")
+		_, err := GetScopedProfileLayers(tmp, "../../../etc", "default")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "escapes base path")
 	})
 
 	t.Run("nested_scope_traversal", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := getProfileFiles(tmp, "valid", "../../etc")
+		_, err := GetScopedProfileLayers(tmp, "valid", "../../etc")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "escapes base path")
 	})
 
-
-
 	t.Run("scope_traversal", func(t *testing.T) {
 		t.Parallel()
-		_, _, err := getProfileFiles(tmp, "default", "../../secret")
+		_, err := GetScopedProfileLayers(tmp, "default", "../../secret")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "escapes base path")
 	})
@@ -179,7 +178,7 @@ func TestGetScopedProfileContent(t *testing.T) {
 	})
 }
 
-func Test_readProfileMap(t *testing.T) {
+func Test_readProfile(t *testing.T) {
 	t.Parallel()
 
 	t.Run("valid_file_should_return_data", func(t *testing.T) {
@@ -188,15 +187,15 @@ func Test_readProfileMap(t *testing.T) {
 		profile := path.Join(t.TempDir(), "profile.yml")
 		require.NoError(t, os.WriteFile(profile, []byte("name: profile"), 0600))
 
-		m, err := readProfileMap(profile)
+		layer, err := readProfile(profile, nil)
 		require.NoError(t, err)
-		require.Equal(t, map[string]any{"name": "profile"}, m)
+		require.Equal(t, map[string]any{"name": "profile"}, layer.Values)
 	})
 
 	t.Run("inexistent_file_should_return_error", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := readProfileMap(path.Join(t.TempDir(), "unexistent"))
+		_, err := readProfile(path.Join(t.TempDir(), "unexistent"), nil)
 		require.Error(t, err)
 	})
 
@@ -206,7 +205,7 @@ func Test_readProfileMap(t *testing.T) {
 		profile := path.Join(t.TempDir(), "profile.yml")
 		require.NoError(t, os.WriteFile(profile, []byte(",,,,,"), 0600))
 
-		_, err := readProfileMap(profile)
+		_, err := readProfile(profile, nil)
 		require.Error(t, err)
 	})
 }
@@ -0,0 +1,259 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/guionardo/go/config/crypt"
+	"github.com/guionardo/go/config/merger"
+)
+
+type (
+	// Layer is a profile file and its decoded content.
+	Layer struct {
+		// File is the profile file path.
+		File string
+		// Values is the decoded content, without extends and include keys.
+		Values map[string]any
+		// Lines maps dotted key paths (e.g. "database.host") to their line.
+		Lines map[string]int
+		// Encrypted reports a decrypted profile (see crypt.Extension).
+		Encrypted bool
+	}
+
+	// Option configures profile loading.
+	Option func(*options)
+
+	// Logger receives the profile loading events; *slog.Logger and
+	// config.Logger implementations satisfy it.
+	Logger interface {
+		Debug(msg string, args ...any)
+		Info(msg string, args ...any)
+		Warn(msg string, args ...any)
+		Error(msg string, args ...any)
+	}
+
+	options struct {
+		decrypter crypt.Decrypter
+		logger    Logger
+	}
+
+	// resolver loads profile files with their extends and include
+	// directives, in merge order.
+	resolver struct {
+		options
+		basePath string
+		loaded   map[string]bool
+		stack    []string
+		layers   []Layer
+	}
+)
+
+const (
+	// ExtendsKey lists the scopes a profile extends; they are merged before it.
+	ExtendsKey = "extends"
+
+	// IncludeKey lists the fragment files a profile includes, relative to the
+	// profile directory; they are merged before it.
+	IncludeKey = "include"
+
+	// ScopeSeparator separates scopes in a scope list ("production,staging").
+	ScopeSeparator = ","
+)
+
+var (
+	// ErrProfileCycle is returned when profiles extend or include each other in a cycle.
+	ErrProfileCycle = errors.New("profile cycle")
+
+	// ErrProfileNotFound is returned when a scope profile or an included
+	// fragment has no file.
+	ErrProfileNotFound = errors.New("profile file not found")
+)
+
+// SplitScopes splits a comma-separated scope list, trimming spaces and
+// dropping empty names.
+func SplitScopes(scopes string) []string {
+	var names []string
+
+	for name := range strings.SplitSeq(scopes, ScopeSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// WithDecrypter sets the key used to read encrypted profiles
+// ("production.yaml.enc"); without it they fail to load with crypt.ErrNoKey.
+func WithDecrypter(decrypter crypt.Decrypter) Option {
+	return func(o *options) {
+		o.decrypter = decrypter
+	}
+}
+
+// WithLogger sets the logger of the profile loading events (defaults to
+// slog.Default()).
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func newResolver(basePath string, opts []Option) *resolver {
+	r := &resolver{basePath: basePath, loaded: map[string]bool{}}
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+
+	if r.logger == nil {
+		r.logger = slog.Default()
+	}
+
+	return r
+}
+
+// merged returns the loaded layers merged in order.
+func (r *resolver) merged() map[string]any {
+	maps := make([]map[string]any, len(r.layers))
+	for i, layer := range r.layers {
+		maps[i] = layer.Values
+	}
+
+	return merger.MergeMaps(maps...)
+}
+
+// scope loads the profile of a scope name.
+func (r *resolver) scope(name string) error {
+	if err := checkBasePath(r.basePath, name); err != nil {
+		return err
+	}
+
+	file, err := findYAMLFile(path.Join(r.basePath, name))
+	if err != nil {
+		return err
+	}
+
+	return r.file(file)
+}
+
+// include loads a fragment relative to the directory of the including file.
+func (r *resolver) include(from, name string) error {
+	target := path.Join(path.Dir(from), name)
+	if !withinBasePath(r.basePath, target) {
+		return fmt.Errorf("include %q in %s escapes base path %q", name, from, r.basePath)
+	}
+
+	file, err := findYAMLFile(target)
+	if err != nil {
+		return fmt.Errorf("include %q in %s: %w", name, from, err)
+	}
+
+	return r.file(file)
+}
+
+// file loads a profile file after the scopes it extends and the fragments
+// it includes. A file is merged once, at its first position.
+func (r *resolver) file(file string) error {
+	if slices.Contains(r.stack, file) {
+		return fmt.Errorf("%w: %s", ErrProfileCycle, strings.Join(append(r.stack, file), " -> "))
+	}
+
+	if r.loaded[file] {
+		return nil
+	}
+
+	r.stack = append(r.stack, file)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	layer, err := readProfile(file, r.decrypter)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("profile found", slog.String("file", file), slog.Bool("encrypted", layer.Encrypted))
+
+	m := layer.Values
+
+	extends, err := popStringList(m, ExtendsKey)
+	if err != nil {
+		return fmt.Errorf("profile %s: %w", file, err)
+	}
+
+	includes, err := popStringList(m, IncludeKey)
+	if err != nil {
+		return fmt.Errorf("profile %s: %w", file, err)
+	}
+
+	for _, scope := range extends {
+		for _, name := range SplitScopes(scope) {
+			if err := r.scope(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range includes {
+		if err := r.include(file, name); err != nil {
+			return err
+		}
+	}
+
+	r.loaded[file] = true
+	r.layers = append(r.layers, layer)
+
+	return nil
+}
+
+// popStringList removes key from m and returns its value as a list of
+// strings (a single string is a one-item list).
+func popStringList(m map[string]any, key string) ([]string, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(m, key)
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: expected a list of strings, got %T item", key, item)
+			}
+
+			items[i] = s
+		}
+
+		return items, nil
+	default:
+		return nil, fmt.Errorf("%s: expected a string or a list of strings, got %T", key, value)
+	}
+}
+
+// checkBasePath returns an error if the scope name escapes basePath.
+func checkBasePath(basePath, name string) error {
+	if !withinBasePath(basePath, path.Join(basePath, name)) {
+		return fmt.Errorf("scope %q escapes base path %q", name, basePath)
+	}
+
+	return nil
+}
+
+// withinBasePath reports whether target is basePath or below it.
+func withinBasePath(basePath, target string) bool {
+	base := path.Clean(basePath)
+	target = path.Clean(target)
+
+	return target == base || strings.HasPrefix(target, strings.TrimSuffix(base, "/")+"/")
+}
@@ -0,0 +1,258 @@
+package profile
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/crypt"
+)
+
+func writeProfiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	tmp := t.TempDir()
+	for name, content := range files {
+		filename := path.Join(tmp, name)
+		require.NoError(t, os.MkdirAll(path.Dir(filename), 0o700))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+	}
+
+	return tmp
+}
+
+func TestSplitScopes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"production", "staging"}, SplitScopes(" production, ,staging "))
+	assert.Nil(t, SplitScopes(""))
+}
+
+func TestGetScopedProfileMap_Inheritance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("extends_chain", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := writeProfiles(t, map[string]string{
+			"default.yml":    "name: default\nlevel: 0\nregion: us",
+			"production.yml": "extends: default\nlevel: 1\nreplicas: 3",
+			"staging.yml":    "extends: production\nlevel: 2",
+		})
+
+		m, err := GetScopedProfileMap(tmp, "default", "staging")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "default", "level": 2, "region": "us", "replicas": 3}, m)
+	})
+
+	t.Run("extends_list", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := writeProfiles(t, map[string]string{
+			"default.yml": "name: default",
+			"eu.yml":      "region: eu\nlevel: 1",
+			"large.yml":   "replicas: 9\nlevel: 2",
+			"app.yml":     "extends: [eu, large]\nname: app",
+		})
+
+		m, err := GetScopedProfileMap(tmp, "default", "app")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "app", "region": "eu", "replicas": 9, "level": 2}, m)
+	})
+
+	t.Run("comma_separated_scopes", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := writeProfiles(t, map[string]string{
+			"default.yml":    "level: 0",
+			"production.yml": "level: 1\nreplicas: 3",
+			"staging.yml":    "level: 2",
+		})
+
+		m, err := GetScopedProfileMap(tmp, "default", "production, staging")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"level": 2, "replicas": 3}, m)
+	})
+
+	t.Run("shared_ancestor_merged_once", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := writeProfiles(t, map[string]string{
+			"default.yml":    "level: 0\nname: default",
+			"production.yml": "extends: default\nlevel: 1",
+			"staging.yml":    "extends: [production, default]\nname: staging",
+		})
+
+		m, err := GetScopedProfileMap(tmp, "default", "staging")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"level": 1, "name": "staging"}, m,
+			"default is not merged again over production")
+	})
+
+	t.Run("includes", func(t *testing.T) {
+		t.Parallel()
+
+		tmp := writeProfiles(t, map[string]string{
+			"default.yml":              "include: shared/logging\nname: default\nlog:\n  level: info",
+			"shared/logging.yml":       "include: [database.yaml]\nlog:\n  level: debug\n  format: json",
+			"shared/database.yaml":     "database:\n  port: 5432",
+			"production.yml":           "include: shared/database.yaml\ndatabase:\n  host: prod",
+			"shared/unused/extra.yaml": "unused: true",
+		})
+
+		m, err := GetScopedProfileMap(tmp, "default", "production")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":     "default",
+			"log":      map[string]any{"level": "info", "format": "json"},
+			"database": map[string]any{"port": 5432, "host": "prod"},
+		}, m)
+	})
+}
+
+func TestGetScopedProfileMap_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		files    map[string]string
+		scope    string
+		contains string
+	}{
+		"extends_cycle": {
+			files: map[string]string{
+				"default.yml": "name: default",
+				"a.yml":       "extends: b",
+				"b.yml":       "extends: a",
+			},
+			scope:    "a",
+			contains: "profile cycle",
+		},
+		"self_include": {
+			files: map[string]string{
+				"default.yml": "include: default",
+			},
+			scope:    "default",
+			contains: "profile cycle",
+		},
+		"include_escapes_base_path": {
+			files: map[string]string{
+				"default.yml": "include: ../outside",
+			},
+			scope:    "default",
+			contains: "escapes base path",
+		},
+		"extends_escapes_base_path": {
+			files: map[string]string{
+				"default.yml": "extends: ../../etc/passwd",
+			},
+			scope:    "default",
+			contains: "escapes base path",
+		},
+		"scope_list_escapes_base_path": {
+			files: map[string]string{
+				"default.yml": "name: default",
+			},
+			scope:    "default,../secret",
+			contains: "escapes base path",
+		},
+		"missing_include": {
+			files: map[string]string{
+				"default.yml": "include: missing",
+			},
+			scope:    "default",
+			contains: "file not found",
+		},
+		"missing_extended_scope": {
+			files: map[string]string{
+				"default.yml": "extends: missing",
+			},
+			scope:    "default",
+			contains: "file not found",
+		},
+		"invalid_extends": {
+			files: map[string]string{
+				"default.yml": "extends: {a: b}",
+			},
+			scope:    "default",
+			contains: "expected a string or a list of strings",
+		},
+		"invalid_include_item": {
+			files: map[string]string{
+				"default.yml": "include: [1]",
+			},
+			scope:    "default",
+			contains: "expected a list of strings",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := GetScopedProfileMap(writeProfiles(t, tc.files), "default", tc.scope)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.contains)
+		})
+	}
+
+	t.Run("sibling_prefix_is_not_inside_base_path", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, withinBasePath("/etc/app", "/etc/app/shared/x.yml"))
+		assert.True(t, withinBasePath("/etc/app", "/etc/app"))
+		assert.False(t, withinBasePath("/etc/app", "/etc/app2/x.yml"))
+	})
+}
+
+func TestGetScopedProfileLayers_Encrypted(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	ciphertext, err := key.Encrypt([]byte("database:\n  password: s3cr3t\n"))
+	require.NoError(t, err)
+
+	tmp := writeProfiles(t, map[string]string{
+		"default.yml":         "name: app\n",
+		"production.yaml.enc": string(ciphertext),
+	})
+
+	layers, err := GetScopedProfileLayers(tmp, "default", "production", WithDecrypter(key))
+	require.NoError(t, err)
+	require.Len(t, layers, 2)
+	assert.False(t, layers[0].Encrypted)
+	assert.True(t, layers[1].Encrypted)
+	assert.Equal(t, path.Join(tmp, "production.yaml.enc"), layers[1].File)
+	assert.Equal(t, map[string]any{"database": map[string]any{"password": "s3cr3t"}}, layers[1].Values)
+	assert.Equal(t, 2, layers[1].Lines["database.password"])
+
+	_, err = GetScopedProfileLayers(tmp, "default", "production")
+	require.ErrorIs(t, err, crypt.ErrNoKey)
+
+	other, err := crypt.GenerateAESKey()
+	require.NoError(t, err)
+
+	_, err = GetScopedProfileLayers(tmp, "default", "production", WithDecrypter(other))
+	require.Error(t, err)
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	tmp := writeProfiles(t, map[string]string{
+		"default.yml":    "name: default",
+		"production.yml": "extends: default\nlevel: 1",
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := GetScopedProfileMap(tmp, "default", "production", WithLogger(logger))
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `msg="profile found" file=`+path.Join(tmp, "default.yml")+" encrypted=false")
+	assert.Contains(t, buf.String(), `msg="profile found" file=`+path.Join(tmp, "production.yml"))
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/guionardo/go/config/merger"
+)
+
+type (
+	// Origin tells where the value of a configuration field came from.
+	Origin struct {
+		// Source is OriginProfile, OriginEnv, OriginDefault, OriginUpdate
+		// or the name of a configuration source (e.g. "json:app.json").
+		Source string
+		// Location is the "file:line" of a profile value or the environment
+		// variable name (for OriginDefault, the variable that was not set).
+		Location string
+		// Value is the current field value, masked for `safe` fields and
+		// resolved secrets.
+		Value any
+	}
+
+	// Provenance maps dotted field paths (e.g. "Database.Host") to the
+	// origin of their value.
+	Provenance map[string]Origin
+
+	// loadMeta is what the provider knows about the current configuration
+	// besides its value.
+	loadMeta struct {
+		// secretPaths are the field paths holding resolved secret references.
+		secretPaths []string
+		// origins are the field origins, without values.
+		origins map[string]Origin
+	}
+
+	// mapLayer is a map configuration layer waiting to be decoded.
+	mapLayer struct {
+		source    string
+		file      string
+		lines     map[string]int
+		values    map[string]any
+		encrypted bool
+	}
+)
+
+const (
+	// OriginProfile is the source of values read from YAML profiles.
+	OriginProfile = "profile"
+	// OriginEnv is the source of values read from environment variables.
+	OriginEnv = "env"
+	// OriginDefault is the source of values read from `default` struct tags.
+	OriginDefault = "default"
+	// OriginUpdate is the source of values set by UpdateConfiguration.
+	OriginUpdate = "update"
+)
+
+// WithProvenanceLog logs the origin of every field once the configuration
+// is first loaded (see Provider.Explain).
+func WithProvenanceLog() providerOption {
+	return func(p *provider) {
+		p.logProvenance = true
+	}
+}
+
+// Explain returns the origin of every field set while loading the
+// configuration (and of the fields changed by UpdateConfiguration since).
+// Fields left at their zero value are not reported. It is empty until the
+// configuration is loaded. Safe for concurrent use.
+func (p *Provider[T]) Explain() Provenance {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.meta.provenance(p.configuration)
+}
+
+// Paths returns the field paths, sorted.
+func (p Provenance) Paths() []string {
+	return slices.Sorted(maps.Keys(p))
+}
+
+// String formats one "Path = value (source location)" line per field, sorted by path.
+func (p Provenance) String() string {
+	lines := make([]string, 0, len(p))
+	for _, path := range p.Paths() {
+		lines = append(lines, fmt.Sprintf("%s = %v (%s)", path, p[path].Value, p[path]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// String formats the origin as "source location".
+func (o Origin) String() string {
+	return strings.TrimSpace(o.Source + " " + o.Location)
+}
+
+// provenance returns the origins with the values of configuration.
+func (m loadMeta) provenance(configuration any) Provenance {
+	fields := flattenFields(configuration)
+	provenance := make(Provenance, len(m.origins))
+
+	for path, origin := range m.origins {
+		field := fields[path]
+
+		origin.Value = field.value
+		if field.safe || isMaskedPath(path, m.secretPaths) {
+			origin.Value = maskedValue
+		}
+
+		provenance[path] = origin
+	}
+
+	return provenance
+}
+
+// logAttr returns the origins as a log group.
+func (m loadMeta) logAttr() slog.Attr {
+	attrs := make([]any, 0, len(m.origins))
+	for _, path := range slices.Sorted(maps.Keys(m.origins)) {
+		attrs = append(attrs, slog.String(path, m.origins[path].String()))
+	}
+
+	return slog.Group("provenance", attrs...)
+}
+
+// withUpdates returns a copy of the metadata where the changed fields come from UpdateConfiguration.
+func (m loadMeta) withUpdates(changes Changes) loadMeta {
+	updated := loadMeta{secretPaths: m.secretPaths, origins: maps.Clone(m.origins)}
+	if updated.origins == nil {
+		updated.origins = make(map[string]Origin, len(changes))
+	}
+
+	for _, change := range changes {
+		updated.origins[change.Path] = Origin{Source: OriginUpdate}
+	}
+
+	return updated
+}
+
+// recordOrigins sets the origin of every leaf value of the merged layers,
+// by the field path of t it decodes into, to the layer that won it in the
+// merge (see merger.MergeWithOrigins).
+func recordOrigins(t reflect.Type, merged map[string]any, layers []mapLayer, layerOf merger.Origins,
+	origins map[string]Origin,
+) {
+	walkMapLeaves(merged, nil, func(yamlPath []string) {
+		layer, ok := layerOf[strings.Join(yamlPath, ".")]
+		if !ok {
+			return
+		}
+
+		if path := goFieldPath(t, yamlPath); path != "" {
+			origins[path] = layers[layer].origin(yamlPath)
+		}
+	})
+}
+
+// origin returns the origin of the value at yamlPath in the layer.
+func (l mapLayer) origin(yamlPath []string) Origin {
+	origin := Origin{Source: l.source}
+	if line, ok := l.lines[strings.Join(yamlPath, ".")]; ok {
+		origin.Location = fmt.Sprintf("%s:%d", l.file, line)
+	}
+
+	return origin
+}
+
+// walkMapLeaves visits the key path of every non-map value of m.
+func walkMapLeaves(m map[string]any, parent []string, visit func(path []string)) {
+	for key, value := range m {
+		path := append(parent[:len(parent):len(parent)], key)
+		if child, ok := value.(map[string]any); ok && len(child) > 0 {
+			walkMapLeaves(child, path, visit)
+			continue
+		}
+
+		visit(path)
+	}
+}
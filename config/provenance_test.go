@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/source"
+)
+
+type provenanceConfig struct {
+	Name     string    `yaml:"name"`
+	Port     int       `yaml:"port" env:"PROVCFG_PORT" default:"8080"`
+	Host     string    `yaml:"host" env:"PROVCFG_HOST"`
+	Password string    `yaml:"password"`
+	Token    string    `yaml:"token" safe:"true"`
+	Started  time.Time `yaml:"started"`
+	Database struct {
+		Name string `yaml:"name"`
+		Pool int    `yaml:"pool"`
+	} `yaml:"database"`
+}
+
+func TestProviderExplain(t *testing.T) {
+	t.Setenv("PROVCFG_HOST", "env-host")
+	t.Setenv("PROVCFG_PASSWORD", "s3cr3t")
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"),
+		[]byte("name: default\ntoken: abc\ndatabase:\n  name: app\n  pool: 1\n"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(tmp, "production.yml"),
+		[]byte("database:\n  pool: 10\npassword: ${env:PROVCFG_PASSWORD}\n"), 0600))
+
+	provider := NewProvider[provenanceConfig](
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("production"),
+		WithSources(source.Map("overrides", map[string]any{"started": "2026-01-02T03:04:05Z"})),
+		WithProvenanceLog(),
+	)
+
+	assert.Empty(t, provider.Explain(), "nothing is explained before loading")
+
+	_, err := provider.GetConfiguration()
+	require.NoError(t, err)
+
+	defaultFile := path.Join(tmp, "default.yml")
+	productionFile := path.Join(tmp, "production.yml")
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, Provenance{
+		"Name":          {Source: OriginProfile, Location: defaultFile + ":1", Value: "default"},
+		"Token":         {Source: OriginProfile, Location: defaultFile + ":2", Value: maskedValue},
+		"Database.Name": {Source: OriginProfile, Location: defaultFile + ":4", Value: "app"},
+		"Database.Pool": {Source: OriginProfile, Location: productionFile + ":2", Value: 10},
+		"Password":      {Source: OriginProfile, Location: productionFile + ":3", Value: maskedValue},
+		"Started":       {Source: "overrides", Value: started},
+		"Host":          {Source: OriginEnv, Location: "PROVCFG_HOST", Value: "env-host"},
+		"Port":          {Source: OriginDefault, Location: "PROVCFG_PORT", Value: 8080},
+	}, provider.Explain())
+
+	t.Run("update_configuration", func(t *testing.T) {
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+
+		cfg.Port = 9090
+		require.NoError(t, provider.UpdateConfiguration(cfg))
+
+		explained := provider.Explain()
+		assert.Equal(t, Origin{Source: OriginUpdate, Value: 9090}, explained["Port"])
+		assert.Equal(t, OriginEnv, explained["Host"].Source)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		text := provider.Explain().String()
+		assert.Contains(t, text, "Host = env-host (env PROVCFG_HOST)\n")
+		assert.Contains(t, text, "Password = ******** (profile "+productionFile+":3)")
+		assert.NotContains(t, text, "s3cr3t")
+		assert.NotContains(t, text, "abc")
+	})
+}
+
+func TestProviderExplainMergeResult(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"),
+		[]byte("name: default\nhost: db\ndatabase:\n  name: app\n"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(tmp, "production.yml"),
+		[]byte("name: null\nhost:\n  nested: value\n"), 0600))
+
+	provider := NewProvider[provenanceConfig](
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("production"),
+		WithMergeOptions(merger.WithNullDelete()),
+	)
+
+	cfg, err := provider.GetConfiguration()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Name)
+	assert.Equal(t, "db", cfg.Host)
+
+	defaultFile := path.Join(tmp, "default.yml")
+	explained := provider.Explain()
+
+	assert.NotContains(t, explained, "Name", "a deleted value has no origin")
+	assert.Equal(t, Origin{Source: OriginProfile, Location: defaultFile + ":2", Value: "db"}, explained["Host"],
+		"a skipped conflicting value keeps the earlier origin")
+	assert.Equal(t, defaultFile+":4", explained["Database.Name"].Location)
+}
+
+func TestWalkFieldsOpaqueStructs(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := flattenFields(provenanceConfig{Started: started})
+
+	assert.Equal(t, started, fields["Started"].value, "time.Time is a leaf")
+	assert.NotContains(t, fields, "Started.wall")
+}
+
+func TestOriginString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "env APP_PORT", Origin{Source: OriginEnv, Location: "APP_PORT"}.String())
+	assert.Equal(t, "update", Origin{Source: OriginUpdate}.String())
+}
@@ -1,15 +1,17 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/merger"
 	"github.com/guionardo/go/config/profile"
-	"gopkg.in/yaml.v3"
+	"github.com/guionardo/go/config/source"
 )
 
 // providerOption is a functional option for configuring a Provider.
@@ -25,6 +27,17 @@ type Provider[T any] struct {
 	lock          sync.RWMutex
 	configuration T
 	loaded        bool
+
+	watchLock   sync.Mutex
+	watchers    map[chan Change[T]]struct{}
+	stopWatch   context.CancelFunc
+	subscribers map[uint64]func(old, new T)
+	nextSubID   uint64
+
+	meta loadMeta
+
+	history []snapshotEntry[T]
+	version uint64
 }
 
 // Logger defines the logging interface used by Provider for configuration events.
@@ -61,6 +74,9 @@ func NewProvider[T any](options ...providerOption) *Provider[T] {
 // GetConfiguration returns the current configuration, loading it from YAML
 // profiles and environment variables on the first call. Subsequent calls
 // return the cached configuration. Safe for concurrent use.
+//
+// A profile that cannot be read is logged as a warning unless
+// WithProfileRequired is set; Load returns every load error instead.
 func (p *Provider[T]) GetConfiguration() (T, error) {
 	p.lock.RLock()
 	if p.loaded {
@@ -73,7 +89,7 @@ func (p *Provider[T]) GetConfiguration() (T, error) {
 	defer p.lock.Unlock()
 
 	if !p.loaded {
-		if err := p.loadStaticConfiguration(); err != nil {
+		if err := p.loadStaticConfiguration(context.Background()); err != nil {
 			return p.configuration, err
 		}
 	}
@@ -81,66 +97,233 @@ func (p *Provider[T]) GetConfiguration() (T, error) {
 }
 
 // UpdateConfiguration replaces the current configuration and re-validates it.
-// Returns an error if validation fails. Safe for concurrent use.
+// Returns an error if validation fails. Watchers are notified when the
+// configuration actually changes, and the previous one stays in History for
+// Rollback. Safe for concurrent use.
 func (p *Provider[T]) UpdateConfiguration(configuration T) error {
 	p.lock.Lock()
-	defer p.lock.Unlock()
+	old := p.configuration
+	meta := p.meta.withUpdates(Diff(old, configuration))
+	changed, err := p.updateConfiguration(configuration, meta, SnapshotUpdate)
+	secretPaths := p.meta.secretPaths
+	p.lock.Unlock()
+
+	if changed {
+		p.notify(Change[T]{Old: old, New: configuration}, secretPaths)
+	}
 
-	return p.updateConfiguration(configuration)
+	return err
 }
 
-func (p *Provider[T]) updateConfiguration(configuration T) error {
+// updateConfiguration validates and stores the configuration with its
+// metadata, reporting whether it differs from the previous one. A changed
+// configuration (or the first one) is recorded in the history as a
+// snapshot of source.
+// Caller MUST hold p.lock write lock.
+func (p *Provider[T]) updateConfiguration(configuration T, meta loadMeta, source SnapshotSource) (bool, error) {
 	if err := p.validateConfiguration(configuration); err != nil {
-		logger().Error("error validating configuration", "error", err)
-		return err
+		p.log().Error("configuration validation failed", validationLog(configuration, err, meta.secretPaths)...)
+		return false, err
 	}
 
+	p.meta = meta
+
 	// Compare the configuration with the previous configuration
 	if reflect.DeepEqual(p.configuration, configuration) {
-		logger().Info("configuration is the same as the previous configuration, skipping update")
-		return nil
+		p.log().Info("configuration is the same as the previous configuration, skipping update")
+
+		if len(p.history) == 0 || source == SnapshotRollback {
+			p.record(configuration, meta, source)
+		}
+
+		return false, nil
 	}
 
 	p.configuration = configuration
 	p.loaded = true
+	p.record(configuration, meta, source)
 
-	logger().Info("configuration updated", getConfigurationLog(configuration))
+	p.log().Info("configuration updated", getConfigurationLog(configuration, meta.secretPaths...))
 
-	return nil
+	return true, nil
 }
 
 // loadStaticConfiguration loads the static configuration from the scope files and the environment variables.
 // Caller MUST hold p.lock write lock.
-func (p *Provider[T]) loadStaticConfiguration() error {
+func (p *Provider[T]) loadStaticConfiguration(ctx context.Context) error {
+	configuration, meta, readErr := p.readConfiguration(ctx, p.profileRequired)
+	if isStrictError(readErr) {
+		return readErr
+	}
+
+	if _, err := p.updateConfiguration(configuration, meta, SnapshotLoad); err != nil {
+		return err
+	}
+
+	if p.logProvenance {
+		p.log().Info("configuration provenance", meta.logAttr())
+	}
+
+	return readErr
+}
+
+// readConfiguration builds a configuration from the configuration layers
+// (scope files, extra sources and environment variables), without
+// validating or storing it, and records the origin of every field. It
+// stops with ctx, which also bounds the secret resolvers.
+// Secret references in map layers are resolved and their field paths, like
+// those of encrypted profile values, recorded so they can be masked like
+// `safe` fields.
+// Profile read errors are logged as warnings unless profileRequired is set;
+// source, secret, parse and (with StrictFail) strict mode errors are
+// returned joined, alongside the best-effort configuration.
+func (p *Provider[T]) readConfiguration(ctx context.Context, profileRequired bool) (T, loadMeta, error) {
 	var (
 		configuration T
 		errs          []error
+		pending       []mapLayer
+		maskedPaths   [][]string
+		origins       = map[string]Origin{}
+		typeOf        = reflect.TypeFor[T]()
 	)
 
-	if profilesPath := p.getProfilesPath(); profilesPath == "" {
-		slog.Info("no profiles path found, skipping profile loading")
-	} else {
-		content, err := profile.GetScopedProfileContent(p.profilesPath, p.defaultScope, p.scope)
-		if err != nil {
-			logger().Warn("error reading profile", "error", err)
-		} else if err := yaml.Unmarshal(content, &configuration); err != nil {
-			logger().Error("error unmarshalling profile", "error", err)
+	layers := p.layers()
+
+	lookup, environ, err := envLookup(layers)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	secrets := p.newSecretInterpolator(ctx, lookup)
+
+	decode := func() {
+		if len(pending) == 0 {
+			return
+		}
+
+		var violations []error
+
+		values := make([]map[string]any, len(pending))
+		for i, layer := range pending {
+			maskedPaths = append(maskedPaths, layer.encryptedPaths()...)
+			violations = append(violations, layer.unknownKeys(typeOf)...)
+			values[i] = layer.values
+		}
+
+		merged, conflicts, mergeOrigins := merger.MergeWithOrigins(values, p.mergeOptions...)
+		recordOrigins(typeOf, merged, pending, mergeOrigins, origins)
+		masked := secretFieldPaths(typeOf, append(slices.Clone(maskedPaths), secrets.paths...))
+		violations = append(violations, typeMismatches(typeOf, pending, conflicts, masked)...)
+		errs = append(errs, p.strictErrors(violations)...)
+		pending = nil
+
+		if err := secrets.interpolate(merged); err != nil {
+			p.log().Error("error resolving secrets", "error", err)
+			errs = append(errs, fmt.Errorf("secrets: %w", err))
+		}
+
+		if err := decodeMap(&configuration, merged); err != nil {
+			p.log().Error("error unmarshalling profile", "error", err)
 			errs = append(errs, fmt.Errorf("yaml: %w", err))
 		}
 	}
 
-	if err := environment.ParseEnvironment(&configuration, nil); err != nil {
-		logger().Error("error parsing environment", "error", err)
-		errs = append(errs, fmt.Errorf("env: %w", err))
+	for _, layer := range layers {
+		if err := ctx.Err(); err != nil {
+			return configuration, loadMeta{}, errors.Join(append(errs, err)...)
+		}
+
+		switch layer {
+		case source.Profiles:
+			profileLayers, err := p.readProfiles(profileRequired)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			pending = append(pending, profileLayers...)
+		case source.Environment:
+			decode()
+
+			opts := append(slices.Clone(p.envOptions),
+				environment.WithLookup(lookup),
+				environment.WithEnviron(environ),
+				environment.WithLogger(p.log()),
+				environment.WithOnSet(func(a environment.Assignment) {
+					origins[a.Field] = envOrigin(a)
+				}))
+			if err := environment.Parse(&configuration, opts...); err != nil {
+				p.log().Error("error parsing environment", "error", err)
+				errs = append(errs, fmt.Errorf("env: %w", err))
+			}
+
+			errs = append(errs, p.strictErrors(unknownEnv(typeOf, p.envPrefix, environ, p.envOptions))...)
+		default:
+			layerMap, err := layer.Load()
+			if err != nil {
+				p.log().Error("error loading source", "source", layer.Name(), "error", err)
+				errs = append(errs, fmt.Errorf("source %s: %w", layer.Name(), err))
+			} else if layerMap != nil {
+				if source.IsUntrusted(layer) {
+					if err := secrets.interpolateUntrusted(layerMap); err != nil {
+						p.log().Error("error resolving secrets", "source", layer.Name(), "error", err)
+						errs = append(errs, fmt.Errorf("secrets: source %s: %w", layer.Name(), err))
+					}
+				}
+
+				pending = append(pending, mapLayer{source: layer.Name(), values: layerMap})
+			}
+		}
 	}
 
-	if err := p.updateConfiguration(configuration); err != nil {
-		return err
+	decode()
+
+	meta := loadMeta{secretPaths: secretFieldPaths(typeOf, append(maskedPaths, secrets.paths...)), origins: origins}
+
+	return configuration, meta, errors.Join(errs...)
+}
+
+// readProfiles returns the scope profile layers, or nil when there is no
+// profiles path or (unless profileRequired) the profiles cannot be read.
+func (p *Provider[T]) readProfiles(profileRequired bool) ([]mapLayer, error) {
+	if profilesPath := p.getProfilesPath(); profilesPath == "" {
+		p.log().Info("no profiles path found, skipping profile loading")
+		return nil, nil
+	}
+
+	profileLayers, err := profile.GetScopedProfileLayers(p.profilesPath, p.defaultScope, p.scope,
+		profile.WithDecrypter(p.getDecrypter()), profile.WithLogger(p.log()))
+	switch {
+	case err != nil && profileRequired:
+		return nil, fmt.Errorf("profile: %w", err)
+	case err != nil:
+		p.log().Warn("error reading profile", "error", err)
+		return nil, nil
 	}
 
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+	return profileMapLayers(profileLayers), nil
+}
+
+// profileMapLayers converts the profile layers to merge layers.
+func profileMapLayers(profileLayers []profile.Layer) []mapLayer {
+	layers := make([]mapLayer, len(profileLayers))
+	for i, layer := range profileLayers {
+		layers[i] = mapLayer{
+			source:    OriginProfile,
+			file:      layer.File,
+			lines:     layer.Lines,
+			values:    layer.Values,
+			encrypted: layer.Encrypted,
+		}
+	}
+
+	return layers
+}
+
+// envOrigin returns the origin of a field set by the environment stage.
+func envOrigin(a environment.Assignment) Origin {
+	if a.Default {
+		return Origin{Source: OriginDefault, Location: a.Env}
 	}
 
-	return nil
+	return Origin{Source: OriginEnv, Location: a.Env}
 }
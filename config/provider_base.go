@@ -2,17 +2,32 @@ package config
 
 import (
 	"log/slog"
+	"time"
 
+	"github.com/guionardo/go/config/crypt"
 	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/source"
 	"github.com/guionardo/go/config/validation"
 )
 
 type (
 	provider struct {
-		profilesPath string
-		logger       Logger
-		scope        string
-		defaultScope string
+		profilesPath    string
+		logger          Logger
+		scope           string
+		defaultScope    string
+		watchInterval   time.Duration
+		envPrefix       string
+		envOptions      []environment.Option
+		sources         []source.Source
+		mergeOptions    []merger.Option
+		secretResolvers map[string]SecretResolver
+		logProvenance   bool
+		strict          StrictMode
+		decrypter       crypt.Decrypter
+		profileRequired bool
+		historySize     int
 	}
 )
 
@@ -20,19 +35,15 @@ func (p *provider) getProfilesPath() string {
 	if p.profilesPath == "" {
 		p.profilesPath = environment.GetEnv(EnvProfilesPath)
 		if p.profilesPath != "" {
-			slog.Warn("profiles path found in environment variables", slog.String("profilesPath", p.profilesPath))
+			p.log().Warn("profiles path found in environment variables", slog.String("profilesPath", p.profilesPath))
 		}
 	}
 
 	return p.profilesPath
 }
 
+// validateConfiguration runs the Validator method and the tag rules of the
+// configuration, returning all the problems.
 func (p *provider) validateConfiguration(configuration any) error {
-	if validator, ok := configuration.(validation.Validator); ok {
-		if err := validator.Validate(); err != nil {
-			return err
-		}
-	}
-
 	return validation.Validate(configuration)
 }
@@ -0,0 +1,33 @@
+// Package schema generates documentation for configuration structs.
+//
+// It walks the same struct tags the config packages read (yaml, env,
+// envPrefix, default, validate, safe) plus an optional `description` tag
+// and produces:
+//   - Fields: a flat list of settings (YAML key, env var, type, default, rules)
+//   - JSONSchema: a draft 2020-12 JSON Schema for editor validation and
+//     autocompletion of YAML profiles (validator rules become keywords where
+//     possible; env names and raw rules are kept as x-env and x-validate)
+//   - Markdown: a reference table of every setting
+//
+// The fields of slice and map elements are listed after the collection, with
+// "replicas[].host" / "upstreams.*.url" keys and the indexed or keyed
+// variable names ("APP_REPLICAS_{N}_HOST").
+//
+// The output stays in sync with the code when it is regenerated by a small
+// program run from go:generate:
+//
+//	//go:generate go run ./internal/configdocs
+//
+//	// internal/configdocs/main.go
+//	func main() {
+//	    err := schema.WriteFiles[config.AppConfig]("config.schema.json", "CONFIG.md",
+//	        schema.WithTitle("App configuration"),
+//	        schema.WithEnvOptions(environment.WithPrefix("APP_"), environment.WithAutoNaming()))
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+// Pass the same environment options given to config.WithEnvPrefix and
+// config.WithEnvAutoNaming so the documented variable names match.
+package schema
@@ -0,0 +1,23 @@
+package schema_test
+
+import (
+	"fmt"
+
+	"github.com/guionardo/go/config/schema"
+)
+
+func ExampleMarkdown() {
+	type Config struct {
+		Port  int    `yaml:"port" env:"APP_PORT" default:"8080" validate:"min=1"`
+		Token string `yaml:"token" env:"APP_TOKEN" safe:"true" validate:"required"`
+	}
+
+	fmt.Print(schema.Markdown[Config](schema.WithTitle("Settings")))
+	// Output:
+	// # Settings
+	//
+	// | Key | Environment | Type | Default | Validation | Notes |
+	// |-----|-------------|------|---------|------------|-------|
+	// | `port` | `APP_PORT` | `int` | `8080` | `min=1` |  |
+	// | `token` | `APP_TOKEN` | `string` |  | `required` | **required**; **secret** (masked in logs) |
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFiles writes the JSON Schema and the Markdown reference of T, for
+// use from a go:generate program. An empty path skips that output.
+func WriteFiles[T any](jsonSchemaPath, markdownPath string, opts ...Option) error {
+	if jsonSchemaPath != "" {
+		content, err := JSONSchema[T](opts...)
+		if err != nil {
+			return err
+		}
+
+		if err := writeFile(jsonSchemaPath, append(content, '\n')); err != nil {
+			return err
+		}
+	}
+
+	if markdownPath != "" {
+		if err := writeFile(markdownPath, []byte(Markdown[T](opts...))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeFile(name string, content []byte) error {
+	if err := os.WriteFile(filepath.Clean(name), content, 0o644); err != nil { //nolint:gosec // documentation files
+		return fmt.Errorf("config/schema: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches time.ParseDuration strings such as "1h30m".
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// JSONSchema returns the JSON Schema of the YAML (or JSON) configuration
+// accepted for T. Besides the standard keywords, properties carry
+// "x-env" (environment variable) and "x-validate" (validation rules);
+// `safe` fields are marked "writeOnly".
+func JSONSchema[T any](opts ...Option) ([]byte, error) {
+	t := structType(reflect.TypeFor[T]())
+	if t == nil {
+		return nil, fmt.Errorf("config/schema: %s is not a struct", reflect.TypeFor[T]())
+	}
+
+	o := newOptions(t, opts)
+
+	envNames := map[string]string{}
+	for _, f := range fields(t, o) {
+		envNames[f.Path] = f.Env
+	}
+
+	root := objectSchema(t, "", envNames)
+	root["$schema"] = Draft
+	root["title"] = o.title
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// objectSchema returns the schema of a struct type at the Go path parentPath.
+func objectSchema(t reflect.Type, parentPath string, envNames map[string]string) map[string]any {
+	properties := map[string]any{}
+
+	var required []string
+
+	addStructProperties(t, parentPath, envNames, properties, &required)
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+func addStructProperties(
+	t reflect.Type,
+	parentPath string,
+	envNames map[string]string,
+	properties map[string]any,
+	required *[]string,
+) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		key, inline, skip := structfield.YAMLName(field)
+		if skip {
+			continue
+		}
+
+		path := joinPath(parentPath, field.Name)
+		if inline {
+			if nested := structType(field.Type); nested != nil {
+				addStructProperties(nested, path, envNames, properties, required)
+			}
+
+			continue
+		}
+
+		properties[key] = fieldSchema(field, path, envNames)
+
+		if isRequired(field.Tag.Get("validate")) {
+			*required = append(*required, key)
+		}
+	}
+}
+
+// fieldSchema returns the schema of a struct field with its tags applied.
+func fieldSchema(field reflect.StructField, path string, envNames map[string]string) map[string]any {
+	_, safe := field.Tag.Lookup("safe")
+
+	var schema map[string]any
+	if nested := structType(field.Type); nested != nil && !safe {
+		schema = objectSchema(nested, path, envNames)
+	} else {
+		schema = typeSchema(field.Type)
+	}
+
+	if elem, placeholder := elemType(field.Type); elem != nil && !safe {
+		items := objectSchema(elem, joinPath(path, placeholder), envNames)
+		if placeholder == environment.KeyPlaceholder {
+			schema["additionalProperties"] = items
+		} else {
+			schema["items"] = items
+		}
+	}
+
+	if description := field.Tag.Get("description"); description != "" {
+		schema["description"] = description
+	}
+
+	if defaultValue, ok := field.Tag.Lookup("default"); ok {
+		schema["default"] = typedDefault(field.Type, defaultValue)
+	}
+
+	if env := envNames[path]; env != "" {
+		schema["x-env"] = env
+	}
+
+	if safe {
+		schema["writeOnly"] = true
+	}
+
+	if rules := field.Tag.Get("validate"); rules != "" {
+		schema["x-validate"] = rules
+		applyRules(schema, field.Type, validationRules(rules))
+	}
+
+	return schema
+}
+
+// typeSchema returns the schema of a Go type.
+func typeSchema(t reflect.Type) map[string]any { //nolint:cyclop
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == durationType:
+		return map[string]any{"type": "string", "pattern": durationPattern}
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == urlType:
+		return map[string]any{"type": "string", "format": "uri"}
+	case isScalar(t):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+
+		return map[string]any{"type": "array", "items": elemSchema(t.Elem())}
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0 {
+			return map[string]any{"type": "array", "items": typeSchema(t.Key()), "uniqueItems": true}
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": elemSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t, "", nil)
+	default:
+		return map[string]any{}
+	}
+}
+
+func elemSchema(t reflect.Type) map[string]any {
+	if nested := structType(t); nested != nil {
+		return objectSchema(nested, "", nil)
+	}
+
+	return typeSchema(t)
+}
+
+// typedDefault converts a `default` tag to the JSON type of the field.
+func typedDefault(t reflect.Type, value string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if isScalar(t) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
+
+// applyRules maps the validator rules with a JSON Schema equivalent.
+func applyRules(schema map[string]any, t reflect.Type, rules []string) { //nolint:cyclop
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	minKey, maxKey := "minimum", "maximum"
+
+	switch t.Kind() {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		minKey, maxKey = "minItems", "maxItems"
+	case reflect.Map:
+		minKey, maxKey = "minProperties", "maxProperties"
+	}
+
+	numeric := minKey == "minimum"
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "min", "gte":
+			setNumber(schema, minKey, param)
+		case "max", "lte":
+			setNumber(schema, maxKey, param)
+		case "len":
+			setNumber(schema, minKey, param)
+			setNumber(schema, maxKey, param)
+		case "gt":
+			if numeric {
+				setNumber(schema, "exclusiveMinimum", param)
+			}
+		case "lt":
+			if numeric {
+				setNumber(schema, "exclusiveMaximum", param)
+			}
+		case "oneof":
+			enum := []any{}
+			for item := range strings.FieldsSeq(param) {
+				enum = append(enum, typedDefault(t, item))
+			}
+
+			schema["enum"] = enum
+		case "email", "hostname", "ipv4", "ipv6", "uuid":
+			schema["format"] = name
+		case "url", "uri", "http_url":
+			schema["format"] = "uri"
+		}
+	}
+}
+
+func setNumber(schema map[string]any, key, value string) {
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		if n == float64(int64(n)) {
+			schema[key] = int64(n)
+		} else {
+			schema[key] = n
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Markdown returns a Markdown reference of the settings of T: a table with
+// the YAML key, environment variable, type, default, validation rules and
+// notes (description, required, secret) of every field.
+func Markdown[T any](opts ...Option) string {
+	t := structType(reflect.TypeFor[T]())
+	if t == nil {
+		return ""
+	}
+
+	o := newOptions(t, opts)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", o.title)
+	b.WriteString("| Key | Environment | Type | Default | Validation | Notes |\n")
+	b.WriteString("|-----|-------------|------|---------|------------|-------|\n")
+
+	for _, f := range fields(t, o) {
+		defaultValue := ""
+		if f.HasDefault {
+			defaultValue = code(f.Default)
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			code(f.Key), code(f.Env), code(f.Type), defaultValue, code(f.Validate), notes(f))
+	}
+
+	return b.String()
+}
+
+func notes(f Field) string {
+	var parts []string
+
+	if f.Description != "" {
+		parts = append(parts, escape(f.Description))
+	}
+
+	if f.Required {
+		parts = append(parts, "**required**")
+	}
+
+	if f.Safe {
+		parts = append(parts, "**secret** (masked in logs)")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// code formats a table cell as inline code ("" stays empty).
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + escape(s) + "`"
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,250 @@
+package schema
+
+import (
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/internal/structfield"
+)
+
+type (
+	// Field describes one configuration setting.
+	Field struct {
+		// Path is the dotted Go field path, e.g. "Database.Pool.Size", with
+		// a placeholder for the fields of slice and map elements, e.g.
+		// "Replicas.{N}.Host" (see environment.IndexPlaceholder).
+		Path string
+		// Key is the dotted YAML key path, e.g. "database.pool.size",
+		// "replicas[].host" or "upstreams.*.url" ("" for fields hidden
+		// from YAML with `yaml:"-"`).
+		Key string
+		// Type is the Go type, e.g. "int", "[]string", "time.Duration".
+		Type string
+		// Env is the environment variable name ("" if none).
+		Env string
+		// Default is the `default` tag value.
+		Default string
+		// HasDefault reports whether the field has a `default` tag.
+		HasDefault bool
+		// Validate is the `validate` tag (go-playground/validator rules).
+		Validate string
+		// Required reports a "required" validation rule or a required
+		// environment variable (`env:"NAME,required"`).
+		Required bool
+		// Safe reports a `safe` tag: the value is masked in logs.
+		Safe bool
+		// Description is the `description` tag.
+		Description string
+	}
+
+	// Option configures the generators.
+	Option func(*options)
+
+	options struct {
+		title      string
+		envOptions []environment.Option
+	}
+)
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+	urlType      = reflect.TypeFor[url.URL]()
+)
+
+// WithTitle sets the document title (default: the struct type name).
+func WithTitle(title string) Option {
+	return func(o *options) {
+		o.title = title
+	}
+}
+
+// WithEnvOptions sets the environment naming options used by the provider
+// (e.g. environment.WithPrefix, environment.WithAutoNaming), so that the
+// documented variable names match.
+func WithEnvOptions(opts ...environment.Option) Option {
+	return func(o *options) {
+		o.envOptions = append(o.envOptions, opts...)
+	}
+}
+
+func newOptions(t reflect.Type, opts []Option) options {
+	o := options{title: t.Name()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Fields returns the settings of the configuration struct T, in
+// declaration order. Nested structs are flattened into their fields.
+func Fields[T any](opts ...Option) []Field {
+	t := structType(reflect.TypeFor[T]())
+	if t == nil {
+		return nil
+	}
+
+	return fields(t, newOptions(t, opts))
+}
+
+func fields(t reflect.Type, o options) []Field {
+	variables := map[string]environment.Variable{}
+	for _, v := range environment.Variables(t, o.envOptions...) {
+		variables[v.Field] = v
+	}
+
+	var result []Field
+
+	walkStruct(t, "", "", func(field reflect.StructField, path, key string) {
+		if key == "" && variables[path].Name == "" {
+			return
+		}
+
+		defaultValue, hasDefault := field.Tag.Lookup("default")
+		_, safe := field.Tag.Lookup("safe")
+		rules := field.Tag.Get("validate")
+
+		result = append(result, Field{
+			Path:        path,
+			Key:         key,
+			Type:        field.Type.String(),
+			Env:         variables[path].Name,
+			Default:     defaultValue,
+			HasDefault:  hasDefault,
+			Validate:    rules,
+			Required:    isRequired(rules) || variables[path].Required,
+			Safe:        safe,
+			Description: field.Tag.Get("description"),
+		})
+	})
+
+	return result
+}
+
+// walkStruct visits the leaf fields of t with their Go and YAML paths.
+// Fields of inlined structs keep their parent key; `safe` structs are leaves.
+func walkStruct(t reflect.Type, parentPath, parentKey string, visit func(field reflect.StructField, path, key string)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		path := joinPath(parentPath, field.Name)
+
+		key, inline, hidden := structfield.YAMLName(field)
+		switch {
+		case hidden:
+			key = ""
+		case !inline:
+			key = joinPath(parentKey, key)
+		default:
+			key = parentKey
+		}
+
+		_, safe := field.Tag.Lookup("safe")
+		if nested := structType(field.Type); nested != nil && !safe {
+			walkStruct(nested, path, key, visit)
+			continue
+		}
+
+		visit(field, path, key)
+
+		if elem, placeholder := elemType(field.Type); elem != nil && !safe {
+			walkStruct(elem, joinPath(path, placeholder), elemKey(key, placeholder), visit)
+		}
+	}
+}
+
+// elemType returns the struct type of the elements of a slice or a
+// string-keyed map of structs, with the placeholder of their index or key
+// in Go paths (as in environment.Variables), or nil.
+func elemType(t reflect.Type) (reflect.Type, string) {
+	placeholder := environment.IndexPlaceholder
+
+	switch t.Kind() {
+	case reflect.Slice:
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, ""
+		}
+
+		placeholder = environment.KeyPlaceholder
+	default:
+		return nil, ""
+	}
+
+	elem := structType(t.Elem())
+	if elem == nil || elem.NumField() == 0 {
+		return nil, ""
+	}
+
+	return elem, placeholder
+}
+
+// elemKey returns the YAML key path of the elements of a collection at
+// key: "replicas[]" for slices, "upstreams.*" for maps.
+func elemKey(key, placeholder string) string {
+	switch {
+	case key == "":
+		return ""
+	case placeholder == environment.KeyPlaceholder:
+		return key + ".*"
+	default:
+		return key + "[]"
+	}
+}
+
+// structType returns the struct type walked for t (dereferencing pointers),
+// or nil for non-struct and scalar struct types.
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || isScalar(t) {
+		return nil
+	}
+
+	return t
+}
+
+// isScalar reports whether a type is configured from a single string.
+func isScalar(t reflect.Type) bool {
+	return t == durationType || structfield.IsScalarStruct(t)
+}
+
+// validationRules splits a validate tag into its rules, stopping at "dive"
+// (the following rules apply to the elements).
+func validationRules(tag string) []string {
+	var rules []string
+
+	for rule := range strings.SplitSeq(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+
+		if rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func isRequired(tag string) bool {
+	return slices.Contains(validationRules(tag), "required")
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
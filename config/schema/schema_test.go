@@ -0,0 +1,205 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/schema"
+)
+
+type (
+	Common struct {
+		Region string `yaml:"region" env:"REGION" default:"us-east-1" validate:"oneof=us-east-1 sa-east-1"`
+	}
+
+	Database struct {
+		Host     string `yaml:"host" validate:"required,hostname" description:"Database host"`
+		Port     uint16 `yaml:"port" default:"5432" validate:"min=1,max=65535"`
+		Password string `yaml:"password" safe:"true"`
+	}
+
+	AppConfig struct {
+		Common `yaml:",inline"`
+
+		Name     string            `yaml:"name" env:"APP_NAME" validate:"required,min=3"`
+		Timeout  time.Duration     `yaml:"timeout" default:"30s"`
+		Started  time.Time         `yaml:"started"`
+		Tags     []string          `yaml:"tags" validate:"max=5,dive,min=1"`
+		Labels   map[string]string `yaml:"labels"`
+		Ratio    float64           `yaml:"ratio" default:"0.5" validate:"gt=0,lt=1"`
+		Debug    bool              `yaml:"debug" default:"false"`
+		Database Database          `yaml:"database"`
+		Replicas []Database        `yaml:"replicas"`
+		Internal string            `yaml:"-"`
+		Hidden   string            `yaml:"-" env:"HIDDEN"`
+	}
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	fields := schema.Fields[AppConfig](schema.WithEnvOptions(environment.WithAutoNaming()))
+
+	byPath := map[string]schema.Field{}
+	for _, f := range fields {
+		byPath[f.Path] = f
+	}
+
+	assert.Equal(t, schema.Field{
+		Path: "Common.Region", Key: "region", Type: "string", Env: "REGION",
+		Default: "us-east-1", HasDefault: true, Validate: "oneof=us-east-1 sa-east-1",
+	}, byPath["Common.Region"])
+	assert.Equal(t, schema.Field{
+		Path: "Database.Host", Key: "database.host", Type: "string", Env: "DATABASE_HOST",
+		Validate: "required,hostname", Required: true, Description: "Database host",
+	}, byPath["Database.Host"])
+	assert.True(t, byPath["Database.Password"].Safe)
+	assert.Equal(t, "time.Duration", byPath["Timeout"].Type)
+	assert.Equal(t, "time.Time", byPath["Started"].Type)
+	assert.Equal(t, "[]schema_test.Database", byPath["Replicas"].Type)
+	assert.False(t, byPath["Tags"].Required, "rules after dive apply to elements")
+	assert.Equal(t, schema.Field{
+		Path: "Replicas.{N}.Host", Key: "replicas[].host", Type: "string", Env: "REPLICAS_{N}_HOST",
+		Validate: "required,hostname", Required: true, Description: "Database host",
+	}, byPath["Replicas.{N}.Host"])
+
+	assert.Equal(t, "HIDDEN", byPath["Hidden"].Env)
+	assert.Empty(t, byPath["Hidden"].Key)
+	assert.Equal(t, "INTERNAL", byPath["Internal"].Env, "auto naming exposes yaml-hidden fields")
+
+	assert.Equal(t, "Common.Region", fields[0].Path, "declaration order")
+	assert.Nil(t, schema.Fields[string]())
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	content, err := schema.JSONSchema[AppConfig](schema.WithTitle("App"))
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(content, &doc))
+
+	assert.Equal(t, schema.Draft, doc["$schema"])
+	assert.Equal(t, "App", doc["title"])
+	assert.Equal(t, []any{"name"}, doc["required"])
+
+	properties := doc["properties"].(map[string]any)
+	assert.NotContains(t, properties, "internal")
+	assert.NotContains(t, properties, "hidden")
+
+	assert.Equal(t, map[string]any{
+		"type": "string", "default": "us-east-1", "x-env": "REGION",
+		"x-validate": "oneof=us-east-1 sa-east-1", "enum": []any{"us-east-1", "sa-east-1"},
+	}, properties["region"])
+	assert.Equal(t, map[string]any{
+		"type": "string", "x-env": "APP_NAME", "x-validate": "required,min=3", "minLength": float64(3),
+	}, properties["name"])
+	assert.Equal(t, "30s", properties["timeout"].(map[string]any)["default"])
+	assert.Equal(t, "date-time", properties["started"].(map[string]any)["format"])
+	assert.Equal(t, map[string]any{
+		"type": "array", "items": map[string]any{"type": "string"}, "maxItems": float64(5), "x-validate": "max=5,dive,min=1",
+	}, properties["tags"])
+	assert.Equal(t, "object", properties["labels"].(map[string]any)["type"])
+	assert.Equal(t, map[string]any{
+		"type": "number", "default": 0.5, "exclusiveMinimum": float64(0), "exclusiveMaximum": float64(1),
+		"x-validate": "gt=0,lt=1",
+	}, properties["ratio"])
+	assert.Equal(t, false, properties["debug"].(map[string]any)["default"])
+
+	database := properties["database"].(map[string]any)
+	assert.Equal(t, []any{"host"}, database["required"])
+	dbProperties := database["properties"].(map[string]any)
+	assert.Equal(t, "hostname", dbProperties["host"].(map[string]any)["format"])
+	assert.Equal(t, "Database host", dbProperties["host"].(map[string]any)["description"])
+	assert.Equal(t, true, dbProperties["password"].(map[string]any)["writeOnly"])
+	assert.Equal(t, map[string]any{
+		"type": "integer", "minimum": float64(1), "maximum": float64(65535), "default": float64(5432),
+		"x-validate": "min=1,max=65535",
+	}, dbProperties["port"])
+
+	replicas := properties["replicas"].(map[string]any)
+	assert.Equal(t, "object", replicas["items"].(map[string]any)["type"])
+
+	content, err = schema.JSONSchema[AppConfig](schema.WithEnvOptions(environment.WithAutoNaming()))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &doc))
+
+	replicaProperties := doc["properties"].(map[string]any)["replicas"].(map[string]any)["items"].(map[string]any)["properties"]
+	assert.Equal(t, "REPLICAS_{N}_PORT", replicaProperties.(map[string]any)["port"].(map[string]any)["x-env"])
+
+	_, err = schema.JSONSchema[int]()
+	require.Error(t, err)
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	md := schema.Markdown[AppConfig](schema.WithEnvOptions(environment.WithPrefix("MY_")))
+
+	assert.Contains(t, md, "# AppConfig\n")
+	assert.Contains(t, md, "| `name` | `MY_APP_NAME` | `string` |  | `required,min=3` | **required** |\n")
+	assert.Contains(t, md, "| `database.password` |  | `string` |  |  | **secret** (masked in logs) |\n")
+	assert.Contains(t, md, "| `region` | `MY_REGION` | `string` | `us-east-1` | `oneof=us-east-1 sa-east-1` |  |\n")
+	assert.Contains(t, md, "| `database.host` |  | `string` |  | `required,hostname` | Database host; **required** |\n")
+	assert.NotContains(t, md, "internal")
+	assert.Empty(t, schema.Markdown[string]())
+}
+
+func TestWriteFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "config.schema.json")
+	mdPath := filepath.Join(dir, "CONFIG.md")
+
+	require.NoError(t, schema.WriteFiles[AppConfig](jsonPath, mdPath))
+	assert.FileExists(t, jsonPath)
+	assert.FileExists(t, mdPath)
+
+	require.NoError(t, schema.WriteFiles[AppConfig]("", ""))
+	require.Error(t, schema.WriteFiles[AppConfig](filepath.Join(dir, "missing", "x.json"), ""))
+	require.Error(t, schema.WriteFiles[int](jsonPath, ""))
+
+	content, err := os.ReadFile(mdPath)
+	require.NoError(t, err)
+	assert.Equal(t, schema.Markdown[AppConfig](), string(content))
+}
+
+type (
+	zone struct {
+		Zone string `yaml:"zone"`
+	}
+
+	embeddedConfig struct {
+		zone `yaml:",inline"`
+
+		Name  string `yaml:"name"`
+		notes string //nolint:unused // an unexported field is not configuration
+	}
+)
+
+func TestUnexportedFields(t *testing.T) {
+	t.Parallel()
+
+	fields := schema.Fields[embeddedConfig]()
+	require.Len(t, fields, 2, "unexported fields are skipped, but not the embedded struct yaml inlines")
+	assert.Equal(t, schema.Field{Path: "zone.Zone", Key: "zone", Type: "string"}, fields[0])
+	assert.Equal(t, "name", fields[1].Key)
+
+	content, err := schema.JSONSchema[embeddedConfig]()
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(content, &doc))
+	assert.Equal(t, []string{"name", "zone"}, slices.Sorted(maps.Keys(doc["properties"].(map[string]any))))
+}
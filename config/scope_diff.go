@@ -0,0 +1,328 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/profile"
+	"github.com/guionardo/go/config/source"
+)
+
+type (
+	// ScopeTarget is a scope compared by DiffScopes: its profiles and,
+	// optionally, the variables of a .env file applied over them (the
+	// process environment is never read, so both sides are reproducible).
+	ScopeTarget struct {
+		Scope   string `json:"scope" yaml:"scope"`
+		EnvFile string `json:"envFile,omitempty" yaml:"envFile,omitempty"`
+	}
+
+	// KeyChangeKind tells whether a key was added, removed or changed.
+	KeyChangeKind string
+
+	// KeyChange is a configuration key that differs between two scopes.
+	// From and To are masked for `safe` fields and values read from
+	// encrypted profiles.
+	KeyChange struct {
+		// Key is the dotted YAML key, e.g. "database.host" or
+		// "servers.0.host".
+		Key  string        `json:"key" yaml:"key"`
+		Kind KeyChangeKind `json:"kind" yaml:"kind"`
+		From any           `json:"from,omitempty" yaml:"from,omitempty"`
+		To   any           `json:"to,omitempty" yaml:"to,omitempty"`
+	}
+
+	// ScopeDiff is the difference between the configurations of two
+	// scopes, sorted by key.
+	ScopeDiff struct {
+		From    ScopeTarget `json:"from" yaml:"from"`
+		To      ScopeTarget `json:"to" yaml:"to"`
+		Changes []KeyChange `json:"changes" yaml:"changes"`
+	}
+)
+
+const (
+	// KeyAdded is a key set only in the To scope.
+	KeyAdded KeyChangeKind = "added"
+	// KeyRemoved is a key set only in the From scope.
+	KeyRemoved KeyChangeKind = "removed"
+	// KeyChanged is a key set in both scopes with different values.
+	KeyChanged KeyChangeKind = "changed"
+)
+
+// DiffScopes compares the configurations of T resolved for two scopes, e.g.
+// before promoting staging settings to production. Each scope is read like
+// a Provider built with options would read its profiles (default scope,
+// extends, includes, encrypted profiles, merge options), then its EnvFile
+// variables and `default` tags are applied; sources and secret references
+// are left out.
+//
+// Profile errors are returned alone; environment errors and, with
+// WithStrict(StrictFail), the strict violations of the profiles (unknown
+// keys, merge type mismatches) are returned with the diff, like Export.
+func DiffScopes[T any](from, to ScopeTarget, options ...providerOption) (*ScopeDiff, error) {
+	p := NewProvider[T](options...)
+
+	fromValues, fromErr := p.scopeValues(from)
+	toValues, toErr := p.scopeValues(to)
+
+	var profileErr *scopeProfileError
+	if errors.As(fromErr, &profileErr) || errors.As(toErr, &profileErr) {
+		return nil, errors.Join(fromErr, toErr)
+	}
+
+	diff := &ScopeDiff{From: from, To: to, Changes: fromValues.diff(toValues)}
+
+	return diff, errors.Join(fromErr, toErr)
+}
+
+// Empty reports whether the scopes have the same configuration.
+func (d *ScopeDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String formats the diff like a unified diff of keys:
+//
+//	--- staging
+//	+++ production
+//	+ cache.ttl: 5m
+//	- debug: true
+//	~ database.host: db.staging -> db.production
+func (d *ScopeDiff) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", d.From.label(), d.To.label())
+
+	for _, change := range d.Changes {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// Write encodes the diff to w as YAML or JSON.
+func (d *ScopeDiff) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2) //nolint:mnd
+
+		if err := encoder.Encode(d); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(d)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// String formats the change as "+ key: to", "- key: from" or
+// "~ key: from -> to".
+func (c KeyChange) String() string {
+	switch c.Kind {
+	case KeyAdded:
+		return fmt.Sprintf("+ %s: %v", c.Key, c.To)
+	case KeyRemoved:
+		return fmt.Sprintf("- %s: %v", c.Key, c.From)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", c.Key, c.From, c.To)
+	}
+}
+
+// label returns the scope name, with the env file if any.
+func (s ScopeTarget) label() string {
+	if s.EnvFile == "" {
+		return s.Scope
+	}
+
+	return s.Scope + " (" + s.EnvFile + ")"
+}
+
+type (
+	// scopeValues holds the flattened export of a scope configuration:
+	// masked for display and raw for comparison.
+	scopeValues struct {
+		masked map[string]any
+		raw    map[string]any
+	}
+
+	// scopeProfileError is a scope whose profiles could not be read.
+	scopeProfileError struct {
+		scope string
+		err   error
+	}
+)
+
+func (e *scopeProfileError) Error() string {
+	return fmt.Sprintf("scope %s: profile: %v", e.scope, e.err)
+}
+
+func (e *scopeProfileError) Unwrap() error {
+	return e.err
+}
+
+// scopeValues reads the configuration of target and flattens it. The
+// profiles get the strict checks of a load (see WithStrict).
+func (p *Provider[T]) scopeValues(target ScopeTarget) (scopeValues, error) {
+	var (
+		configuration T
+		typeOf        = reflect.TypeFor[T]()
+	)
+
+	profileLayers, err := profile.GetScopedProfileLayers(p.getProfilesPath(), p.defaultScope, target.Scope,
+		profile.WithDecrypter(p.getDecrypter()), profile.WithLogger(p.log()))
+	if err != nil {
+		return scopeValues{}, &scopeProfileError{scope: target.Scope, err: err}
+	}
+
+	var (
+		layers     = profileMapLayers(profileLayers)
+		values     = make([]map[string]any, len(layers))
+		encrypted  [][]string
+		violations []error
+	)
+
+	for i, layer := range layers {
+		values[i] = layer.values
+		encrypted = append(encrypted, layer.encryptedPaths()...)
+		violations = append(violations, layer.unknownKeys(typeOf)...)
+	}
+
+	secretPaths := secretFieldPaths(typeOf, encrypted)
+
+	merged, conflicts := merger.Merge(values, p.mergeOptions...)
+	if err := decodeMap(&configuration, merged); err != nil {
+		return scopeValues{}, &scopeProfileError{scope: target.Scope, err: err}
+	}
+
+	violations = append(violations, typeMismatches(typeOf, layers, conflicts, secretPaths)...)
+
+	var errs []error
+	for _, violation := range p.strictErrors(violations) {
+		errs = append(errs, fmt.Errorf("scope %s: %w", target.Scope, violation))
+	}
+
+	if err := p.applyEnvFile(&configuration, target.EnvFile); err != nil {
+		errs = append(errs, fmt.Errorf("scope %s: env: %w", target.Scope, err))
+	}
+
+	masked := maskedFields(secretPaths)
+	reveal := func(string, bool) bool { return false }
+
+	result := scopeValues{masked: map[string]any{}, raw: map[string]any{}}
+	flattenKeys(exportValue(reflect.ValueOf(configuration), "", masked), "", result.masked)
+	flattenKeys(exportValue(reflect.ValueOf(configuration), "", reveal), "", result.raw)
+
+	return result, errors.Join(errs...)
+}
+
+// applyEnvFile parses the variables of the .env file at path (none if
+// empty) and the `default` tags into configuration.
+func (p *Provider[T]) applyEnvFile(configuration *T, path string) error {
+	env := map[string]string{}
+
+	if path != "" {
+		var err error
+		if env, err = source.DotEnv(path).Environ(); err != nil {
+			return err
+		}
+	}
+
+	opts := append(slices.Clone(p.envOptions),
+		environment.WithLookup(func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}),
+		environment.WithEnviron(slices.Sorted(maps.Keys(env))),
+		environment.WithLogger(p.log()))
+
+	return environment.Parse(configuration, opts...)
+}
+
+// diff returns the keys added, removed and changed from s to other. A
+// masked value is compared by the raw values at and below its key.
+func (s scopeValues) diff(other scopeValues) []KeyChange {
+	keys := slices.Sorted(maps.Keys(s.masked))
+	for key := range other.masked {
+		if _, ok := s.masked[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	changes := []KeyChange{}
+
+	for _, key := range keys {
+		from, inFrom := s.masked[key]
+		to, inTo := other.masked[key]
+
+		switch {
+		case !inFrom:
+			changes = append(changes, KeyChange{Key: key, Kind: KeyAdded, To: to})
+		case !inTo:
+			changes = append(changes, KeyChange{Key: key, Kind: KeyRemoved, From: from})
+		case !reflect.DeepEqual(s.rawBelow(key), other.rawBelow(key)):
+			changes = append(changes, KeyChange{Key: key, Kind: KeyChanged, From: from, To: to})
+		}
+	}
+
+	return changes
+}
+
+// rawBelow returns the raw values at key and below it.
+func (s scopeValues) rawBelow(key string) map[string]any {
+	values := map[string]any{}
+
+	for rawKey, value := range s.raw {
+		if rawKey == key || strings.HasPrefix(rawKey, key+".") {
+			values[rawKey] = value
+		}
+	}
+
+	return values
+}
+
+// flattenKeys adds the leaf values of an export value to keys, by dotted
+// key; list items are keyed by their index. Empty maps and lists are
+// leaves.
+func flattenKeys(value any, key string, keys map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && key != "" {
+			keys[key] = v
+		}
+
+		for name, item := range v {
+			flattenKeys(item, fieldPath(key, name), keys)
+		}
+	case []any:
+		if len(v) == 0 {
+			keys[key] = v
+		}
+
+		for i, item := range v {
+			flattenKeys(item, fieldPath(key, strconv.Itoa(i)), keys)
+		}
+	default:
+		keys[key] = v
+	}
+}
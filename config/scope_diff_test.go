@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/profile"
+)
+
+type scopeDiffConfig struct {
+	Name     string            `yaml:"name"`
+	Port     int               `yaml:"port"`
+	Debug    bool              `yaml:"debug"`
+	Region   string            `yaml:"region" env:"REGION" default:"us"`
+	Token    string            `yaml:"token" safe:"true"`
+	Password string            `yaml:"password" safe:"true"`
+	Labels   map[string]string `yaml:"labels"`
+	Servers  []struct {
+		Host string `yaml:"host"`
+	} `yaml:"servers"`
+}
+
+func TestDiffScopes(t *testing.T) {
+	t.Parallel()
+
+	profilesPath := writeExportProfiles(t, map[string]string{
+		"default":    "name: app\npassword: secret\nservers:\n  - host: a\n",
+		"staging":    "port: 8080\ndebug: true\ntoken: s1\nlabels:\n  team: core\n  tier: \"1\"\n",
+		"production": "port: 443\ntoken: p1\nlabels:\n  team: core\nservers:\n  - host: a\n  - host: b\n",
+	})
+	envFile := path.Join(t.TempDir(), "production.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("APP_REGION=eu\n"), 0o600))
+
+	options := []providerOption{WithProfilesPath(profilesPath), WithDefaultScope("default"), WithEnvPrefix("APP_")}
+
+	diff, err := DiffScopes[scopeDiffConfig](ScopeTarget{Scope: "staging"},
+		ScopeTarget{Scope: "production", EnvFile: envFile}, options...)
+	require.NoError(t, err)
+
+	assert.Equal(t, []KeyChange{
+		{Key: "debug", Kind: KeyChanged, From: true, To: false},
+		{Key: "labels.tier", Kind: KeyRemoved, From: "1"},
+		{Key: "port", Kind: KeyChanged, From: 8080, To: 443},
+		{Key: "region", Kind: KeyChanged, From: "us", To: "eu"},
+		{Key: "servers.1.host", Kind: KeyAdded, To: "b"},
+		{Key: "token", Kind: KeyChanged, From: maskedValue, To: maskedValue},
+	}, diff.Changes)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, "--- staging\n+++ production ("+envFile+")\n"+
+		"~ debug: true -> false\n- labels.tier: 1\n~ port: 8080 -> 443\n~ region: us -> eu\n"+
+		"+ servers.1.host: b\n~ token: ******** -> ********\n", diff.String())
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.Write(&buf, FormatJSON))
+	assert.NotContains(t, buf.String(), "p1")
+
+	var decoded ScopeDiff
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "production", decoded.To.Scope)
+	assert.Len(t, decoded.Changes, 6)
+	require.ErrorIs(t, diff.Write(&buf, Format("toml")), ErrUnknownFormat)
+
+	t.Run("same_scope", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := DiffScopes[scopeDiffConfig](ScopeTarget{Scope: "staging"}, ScopeTarget{Scope: "staging"}, options...)
+		require.NoError(t, err)
+		assert.True(t, diff.Empty())
+	})
+
+	t.Run("missing_scope", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := DiffScopes[scopeDiffConfig](ScopeTarget{Scope: "staging"}, ScopeTarget{Scope: "qa"}, options...)
+		require.ErrorIs(t, err, profile.ErrProfileNotFound)
+		assert.Nil(t, diff)
+	})
+
+	t.Run("env_errors_keep_the_diff", func(t *testing.T) {
+		t.Parallel()
+
+		diff, err := DiffScopes[scopeDiffConfig](ScopeTarget{Scope: "staging"},
+			ScopeTarget{Scope: "production", EnvFile: path.Join(t.TempDir(), "missing.env")}, options...)
+		require.Error(t, err)
+		require.NotNil(t, diff)
+		assert.Contains(t, diff.String(), "~ port: 8080 -> 443")
+	})
+}
+
+func TestDiffScopesStrict(t *testing.T) {
+	t.Parallel()
+
+	profilesPath := writeExportProfiles(t, map[string]string{
+		"default": "name: app\npassword: secret\n",
+		"staging": "name: staging\npassword: 42\nreplicas: 2\n",
+	})
+	from, to := ScopeTarget{Scope: "default"}, ScopeTarget{Scope: "staging"}
+
+	for _, mode := range []StrictMode{StrictOff, StrictWarn} {
+		diff, err := DiffScopes[scopeDiffConfig](from, to, WithProfilesPath(profilesPath), WithStrict(mode))
+		require.NoError(t, err, mode)
+		assert.Len(t, diff.Changes, 1, "the conflicting password is skipped")
+	}
+
+	diff, err := DiffScopes[scopeDiffConfig](from, to, WithProfilesPath(profilesPath), WithStrict(StrictFail))
+	require.ErrorIs(t, err, ErrUnknownKey)
+	require.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Contains(t, err.Error(), `scope staging: unknown key "replicas"`)
+	assert.Contains(t, err.Error(), `type mismatch at "password": int ******** ignored, keeps string ********`)
+
+	require.NotNil(t, diff)
+	assert.Equal(t, []KeyChange{{Key: "name", Kind: KeyChanged, From: "app", To: "staging"}}, diff.Changes)
+}
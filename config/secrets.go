@@ -0,0 +1,314 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	shelltools "github.com/guionardo/go/shell_tools"
+)
+
+type (
+	// SecretResolver resolves secret references written as
+	// ${scheme:reference} in profile and source values.
+	SecretResolver interface {
+		// Resolve returns the secret value for reference.
+		Resolve(ctx context.Context, reference string) (string, error)
+	}
+
+	// SecretResolverFunc adapts a function to SecretResolver.
+	SecretResolverFunc func(ctx context.Context, reference string) (string, error)
+
+	// secretInterpolator replaces secret references in merged layer maps and
+	// records the yaml paths of the values it resolved.
+	secretInterpolator struct {
+		ctx       context.Context
+		resolvers map[string]SecretResolver
+		paths     [][]string
+	}
+)
+
+// SecretCommandTimeout bounds the execution of ${cmd:...} references.
+const SecretCommandTimeout = 30 * time.Second
+
+var (
+	// ErrUnknownSecretScheme is returned for a ${scheme:...} reference without resolver.
+	ErrUnknownSecretScheme = errors.New("unknown secret scheme")
+
+	// ErrSecretNotFound is returned by resolvers when a referenced secret does not exist.
+	ErrSecretNotFound = errors.New("secret not found")
+)
+
+// Resolve calls f(ctx, reference).
+func (f SecretResolverFunc) Resolve(ctx context.Context, reference string) (string, error) {
+	return f(ctx, reference)
+}
+
+// WithSecretResolver registers resolver for ${scheme:reference} values,
+// replacing the resolver of the same scheme. A nil resolver disables the
+// scheme (e.g. WithSecretResolver("env", nil)).
+//
+// Only the env scheme (an environment variable, including .env sources) is
+// built in; file and cmd are enabled by WithFileSecrets and
+// WithCommandSecrets.
+func WithSecretResolver(scheme string, resolver SecretResolver) providerOption {
+	return func(p *provider) {
+		if p.secretResolvers == nil {
+			p.secretResolvers = make(map[string]SecretResolver)
+		}
+
+		p.secretResolvers[scheme] = resolver
+	}
+}
+
+// WithFileSecrets enables ${file:path} references: the content of a file
+// under dir, without the trailing newline. Relative paths are relative to
+// dir; paths (or symlinks) leading out of dir are rejected.
+func WithFileSecrets(dir string) providerOption {
+	return WithSecretResolver("file", fileSecretResolver(dir))
+}
+
+// WithCommandSecrets enables ${cmd:command} references: the output of a
+// command, without the trailing newline. The command line is split like a
+// shell would, but no shell is involved.
+//
+// The command runs with the privileges of the process, so whoever can write
+// a profile or source value can run any program on the host. Enable it only
+// when every configuration layer is as trusted as the binary itself.
+func WithCommandSecrets() providerOption {
+	return WithSecretResolver("cmd", SecretResolverFunc(resolveCommandSecret))
+}
+
+// newSecretInterpolator returns an interpolator with the built-in env
+// resolver (using lookup) and the configured ones, resolving with ctx.
+func (p *provider) newSecretInterpolator(ctx context.Context, lookup func(string) (string, bool)) *secretInterpolator {
+	resolvers := map[string]SecretResolver{
+		"env": SecretResolverFunc(func(_ context.Context, name string) (string, error) {
+			if value, ok := lookup(name); ok {
+				return value, nil
+			}
+
+			return "", fmt.Errorf("%w: environment variable %s", ErrSecretNotFound, name)
+		}),
+	}
+
+	for scheme, resolver := range p.secretResolvers {
+		if resolver == nil {
+			delete(resolvers, scheme)
+			continue
+		}
+
+		resolvers[scheme] = resolver
+	}
+
+	return &secretInterpolator{ctx: ctx, resolvers: resolvers}
+}
+
+// untrusted returns an interpolator for the values of untrusted sources:
+// only the env resolver of s is kept.
+func (s *secretInterpolator) untrusted() *secretInterpolator {
+	resolvers := map[string]SecretResolver{}
+	if env, ok := s.resolvers["env"]; ok {
+		resolvers["env"] = env
+	}
+
+	return &secretInterpolator{ctx: s.ctx, resolvers: resolvers}
+}
+
+// fileSecretResolver reads the files referenced by ${file:...} inside dir.
+func fileSecretResolver(dir string) SecretResolverFunc {
+	return func(_ context.Context, name string) (string, error) {
+		root, err := os.OpenRoot(dir)
+		if err != nil {
+			return "", err
+		}
+		defer root.Close()
+
+		if filepath.IsAbs(name) {
+			base, err := filepath.Abs(dir)
+			if err != nil {
+				return "", err
+			}
+
+			if name, err = filepath.Rel(base, name); err != nil {
+				return "", err
+			}
+		}
+
+		content, err := root.ReadFile(name)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %w", ErrSecretNotFound, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), err
+	}
+}
+
+func resolveCommandSecret(ctx context.Context, command string) (string, error) {
+	args := shelltools.NewQuotedShellArgs(command)
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, SecretCommandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).Output() //nolint:gosec // trusted configuration
+	if err != nil {
+		return "", fmt.Errorf("command %q: %w", args[0], err)
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// interpolate resolves the references in every string of m, in place.
+// Failed references are left untouched and their errors joined.
+func (s *secretInterpolator) interpolate(m map[string]any) error {
+	return s.interpolateValue(m, nil)
+}
+
+// interpolateUntrusted resolves the env references in every string of m,
+// in place, and escapes the remaining ones so that they stay literal text
+// when the merged layers are interpolated. The paths of the resolved
+// values are recorded by s.
+func (s *secretInterpolator) interpolateUntrusted(m map[string]any) error {
+	untrusted := s.untrusted()
+	err := untrusted.interpolateValue(m, nil)
+	s.paths = append(s.paths, untrusted.paths...)
+
+	escapeReferences(m)
+
+	return err
+}
+
+// escapeReferences replaces "${" with the "$${" escape in every string of
+// value, in place.
+func escapeReferences(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if str, ok := item.(string); ok {
+				v[key] = strings.ReplaceAll(str, "${", "$${")
+				continue
+			}
+
+			escapeReferences(item)
+		}
+	case []any:
+		for i, item := range v {
+			if str, ok := item.(string); ok {
+				v[i] = strings.ReplaceAll(str, "${", "$${")
+				continue
+			}
+
+			escapeReferences(item)
+		}
+	}
+}
+
+func (s *secretInterpolator) interpolateValue(value any, path []string) error {
+	var errs []error
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			itemPath := append(path[:len(path):len(path)], key)
+			if str, ok := item.(string); ok {
+				v[key] = s.interpolateString(str, itemPath, &errs)
+				continue
+			}
+
+			errs = append(errs, s.interpolateValue(item, itemPath))
+		}
+	case []any:
+		for i, item := range v {
+			itemPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if str, ok := item.(string); ok {
+				v[i] = s.interpolateString(str, itemPath, &errs)
+				continue
+			}
+
+			errs = append(errs, s.interpolateValue(item, itemPath))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// interpolateString replaces the ${scheme:reference} occurrences of value.
+// "$${" escapes a literal "${"; ${...} without a scheme is left as is.
+func (s *secretInterpolator) interpolateString(value string, path []string, errs *[]error) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+
+	var (
+		b        strings.Builder
+		resolved bool
+	)
+
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			break
+		}
+
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1] + "${")
+			value = value[start+2:]
+
+			continue
+		}
+
+		end := strings.Index(value[start:], "}")
+		scheme, reference, found := strings.Cut(value[start+2:max(start+2, start+end)], ":")
+
+		if end < 0 || !found {
+			b.WriteString(value[:start+2])
+			value = value[start+2:]
+
+			continue
+		}
+
+		b.WriteString(value[:start])
+
+		secret, err := s.resolve(scheme, reference)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", strings.Join(path, "."), err))
+			b.WriteString(value[start : start+end+1])
+		} else {
+			b.WriteString(secret)
+			resolved = true
+		}
+
+		value = value[start+end+1:]
+	}
+
+	b.WriteString(value)
+
+	if resolved {
+		s.paths = append(s.paths, path)
+	}
+
+	return b.String()
+}
+
+func (s *secretInterpolator) resolve(scheme, reference string) (string, error) {
+	resolver, ok := s.resolvers[scheme]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownSecretScheme, scheme)
+	}
+
+	secret, err := resolver.Resolve(s.ctx, reference)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", scheme, err)
+	}
+
+	return secret, nil
+}
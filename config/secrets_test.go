@@ -0,0 +1,284 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/source"
+	httptestmock "github.com/guionardo/go/httptest_mock"
+)
+
+func newTestInterpolator(t *testing.T, opts ...providerOption) *secretInterpolator {
+	t.Helper()
+
+	p := &provider{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p.newSecretInterpolator(context.Background(), func(name string) (string, bool) {
+		value, ok := map[string]string{"DB_PASSWORD": "s3cr3t", "DB_USER": "admin"}[name]
+		return value, ok
+	})
+}
+
+func TestSecretInterpolation(t *testing.T) {
+	t.Parallel()
+
+	secretDir := t.TempDir()
+	secretFile := path.Join(secretDir, "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-token\n"), 0600))
+
+	m := map[string]any{
+		"password": "${env:DB_PASSWORD}",
+		"dsn":      "postgres://${env:DB_USER}:${env:DB_PASSWORD}@db/app",
+		"token":    "${file:" + secretFile + "}",
+		"plain":    "no references",
+		"shell":    "${HOME} and $${env:DB_PASSWORD}",
+		"nested": map[string]any{
+			"list": []any{"a", "${env:DB_USER}", 1},
+		},
+	}
+
+	s := newTestInterpolator(t, WithFileSecrets(secretDir))
+	require.NoError(t, s.interpolate(m))
+
+	assert.Equal(t, "s3cr3t", m["password"])
+	assert.Equal(t, "postgres://admin:s3cr3t@db/app", m["dsn"])
+	assert.Equal(t, "file-token", m["token"])
+	assert.Equal(t, "no references", m["plain"])
+	assert.Equal(t, "${HOME} and ${env:DB_PASSWORD}", m["shell"], "no scheme and escaped references are kept")
+	assert.Equal(t, []any{"a", "admin", 1}, m["nested"].(map[string]any)["list"])
+	assert.ElementsMatch(t, [][]string{{"password"}, {"dsn"}, {"token"}, {"nested", "list", "1"}}, s.paths)
+}
+
+func TestSecretInterpolationErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing_env", func(t *testing.T) {
+		t.Parallel()
+
+		m := map[string]any{"password": "${env:MISSING}"}
+		err := newTestInterpolator(t).interpolate(m)
+		require.ErrorIs(t, err, ErrSecretNotFound)
+		assert.ErrorContains(t, err, "password")
+		assert.Equal(t, "${env:MISSING}", m["password"], "unresolved references are kept")
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestInterpolator(t, WithFileSecrets(t.TempDir()))
+		err := s.interpolate(map[string]any{"token": "${file:secret}"})
+		require.ErrorIs(t, err, ErrSecretNotFound)
+	})
+
+	t.Run("unknown_scheme", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTestInterpolator(t).interpolate(map[string]any{"token": "${vault:secret/app}"})
+		require.ErrorIs(t, err, ErrUnknownSecretScheme)
+	})
+
+	t.Run("disabled_scheme", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestInterpolator(t, WithCommandSecrets(), WithSecretResolver("cmd", nil))
+		err := s.interpolate(map[string]any{"token": "${cmd:echo hi}"})
+		require.ErrorIs(t, err, ErrUnknownSecretScheme)
+	})
+
+	t.Run("file_and_cmd_are_opt_in", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestInterpolator(t)
+		m := map[string]any{"token": "${file:/etc/hostname}", "user": "${cmd:id -un}"}
+		err := s.interpolate(m)
+		require.ErrorIs(t, err, ErrUnknownSecretScheme)
+		assert.Equal(t, map[string]any{"token": "${file:/etc/hostname}", "user": "${cmd:id -un}"}, m)
+	})
+
+	t.Run("file_outside_dir", func(t *testing.T) {
+		t.Parallel()
+
+		parent := t.TempDir()
+		dir := path.Join(parent, "secrets")
+		require.NoError(t, os.Mkdir(dir, 0700))
+		require.NoError(t, os.WriteFile(path.Join(parent, "other"), []byte("other"), 0600))
+
+		names := []string{"../other", path.Join(parent, "other"), "/etc/hostname"}
+		if os.Symlink(path.Join(parent, "other"), path.Join(dir, "link")) == nil {
+			names = append(names, "link")
+		}
+
+		s := newTestInterpolator(t, WithFileSecrets(dir))
+		for _, name := range names {
+			m := map[string]any{"token": "${file:" + name + "}"}
+			require.Error(t, s.interpolate(m), name)
+			assert.Equal(t, "${file:"+name+"}", m["token"])
+		}
+	})
+
+	t.Run("unterminated_reference", func(t *testing.T) {
+		t.Parallel()
+
+		m := map[string]any{"token": "${env:DB_USER"}
+		require.NoError(t, newTestInterpolator(t).interpolate(m))
+		assert.Equal(t, "${env:DB_USER", m["token"])
+	})
+}
+
+func TestSecretResolvers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("custom", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestInterpolator(t, WithSecretResolver("vault", SecretResolverFunc(
+			func(_ context.Context, reference string) (string, error) {
+				if reference == "secret/app" {
+					return "from-vault", nil
+				}
+
+				return "", errors.New("denied")
+			})))
+
+		m := map[string]any{"token": "${vault:secret/app}"}
+		require.NoError(t, s.interpolate(m))
+		assert.Equal(t, "from-vault", m["token"])
+		require.Error(t, s.interpolate(map[string]any{"token": "${vault:other}"}))
+	})
+
+	t.Run("cmd", func(t *testing.T) {
+		t.Parallel()
+
+		if runtime.GOOS == "windows" {
+			t.Skip("echo is a shell builtin on windows")
+		}
+
+		s := newTestInterpolator(t, WithCommandSecrets())
+
+		m := map[string]any{"token": `${cmd:echo "cmd token"}`}
+		require.NoError(t, s.interpolate(m))
+		assert.Equal(t, "cmd token", m["token"])
+
+		require.Error(t, s.interpolate(map[string]any{"token": "${cmd:/nonexistent/command}"}))
+		require.Error(t, s.interpolate(map[string]any{"token": "${cmd:}"}))
+	})
+}
+
+func TestGoFieldPath(t *testing.T) {
+	t.Parallel()
+
+	type (
+		Base struct {
+			Region string `yaml:"region"`
+		}
+		db struct {
+			Password string
+			Hosts    []string `yaml:"hosts"`
+		}
+		cfg struct {
+			Base     `yaml:",inline"`
+			Database *db    `yaml:"database"`
+			Ignored  string `yaml:"-"`
+		}
+	)
+
+	typ := reflect.TypeFor[cfg]()
+	assert.Equal(t, "Database.Password", goFieldPath(typ, []string{"database", "password"}))
+	assert.Equal(t, "Database.Hosts", goFieldPath(typ, []string{"database", "hosts", "0"}))
+	assert.Equal(t, "Base.Region", goFieldPath(typ, []string{"region"}))
+	assert.Empty(t, goFieldPath(typ, []string{"ignored"}))
+	assert.Empty(t, goFieldPath(typ, []string{"unknown"}))
+
+	assert.Equal(t, []string{"Database.Hosts", "Database.Password"}, secretFieldPaths(typ, [][]string{
+		{"database", "password"}, {"database", "hosts", "1"}, {"database", "hosts", "0"}, {"unknown"},
+	}))
+}
+
+func TestProviderSecrets(t *testing.T) {
+	t.Setenv("TESTCFG_SECRET_TAGS", "secret-tags")
+
+	provider := newSourcesProvider(t, source.Map("secrets", map[string]any{
+		"nested": map[string]any{"tags": "${env:TESTCFG_SECRET_TAGS}"},
+	}))
+
+	cfg, err := provider.GetConfiguration()
+	require.NoError(t, err)
+	assert.Equal(t, "secret-tags", cfg.Nested.Tags)
+	assert.Equal(t, []string{"Nested.Tags"}, provider.meta.secretPaths)
+
+	attrs := getConfigurationLog(cfg, provider.meta.secretPaths...).Value.Group()
+	for _, attr := range attrs {
+		if attr.Key == "Nested.Tags" {
+			assert.Equal(t, maskedValue, attr.Value.String())
+		}
+	}
+
+	changes := Diff(cfg, testConfig{Name: cfg.Name}).masked(provider.meta.secretPaths)
+	for _, change := range changes {
+		if change.Path == "Nested.Tags" {
+			assert.Equal(t, maskedValue, change.Old)
+		}
+	}
+}
+
+func TestUntrustedSourceSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("echo is a shell builtin on windows")
+	}
+
+	t.Setenv("REMOTE_SECRET_USER", "remote-user")
+
+	type remoteConfig struct {
+		Name    string `yaml:"name"`
+		Token   string `yaml:"token"`
+		User    string `yaml:"user"`
+		Literal string `yaml:"literal"`
+		Local   string `yaml:"local"`
+	}
+
+	secretDir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(secretDir, "token"), []byte("file-token"), 0600))
+
+	body := "name: ${cmd:echo pwned}\n" +
+		"token: ${file:" + path.Join(secretDir, "token") + "}\n" +
+		"user: ${env:REMOTE_SECRET_USER}\n" +
+		"literal: $${env:REMOTE_SECRET_USER}\n"
+
+	server, _ := httptestmock.SetupServer(t, httptestmock.WithoutLog(), httptestmock.WithRequests(
+		httptestmock.NewMock(http.MethodGet, "/config").WithResponseStatus(http.StatusOK).WithCustomHandler(
+			func(_ httptestmock.Mocker, w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}),
+	))
+
+	provider := NewProvider[remoteConfig](
+		WithSources(
+			source.Map("local", map[string]any{"local": "${cmd:echo trusted}"}),
+			source.HTTP(server.URL+"/config"),
+		),
+		WithCommandSecrets(),
+		WithFileSecrets(secretDir),
+	)
+
+	cfg, err := provider.GetConfiguration()
+	require.ErrorIs(t, err, ErrUnknownSecretScheme)
+	assert.ErrorContains(t, err, "http:"+server.URL)
+
+	assert.Equal(t, "${cmd:echo pwned}", cfg.Name, "remote values never run commands")
+	assert.Equal(t, "${file:"+path.Join(secretDir, "token")+"}", cfg.Token, "remote values never read files")
+	assert.Equal(t, "remote-user", cfg.User, "env references are resolved")
+	assert.Equal(t, "${env:REMOTE_SECRET_USER}", cfg.Literal)
+	assert.Equal(t, "trusted", cfg.Local, "trusted sources keep every resolver")
+}
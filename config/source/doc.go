@@ -0,0 +1,40 @@
+// Package source provides configuration layers for config.Provider.
+//
+// A Source loads a map shaped like the YAML profiles (keys are the yaml
+// field names); the provider deep-merges the layers with merger.MergeMaps,
+// later layers winning, and decodes the result into the configuration struct.
+//
+// Built-in sources:
+//   - JSON, TOML, YAML: files (wrap with Optional to tolerate a missing file)
+//   - Flags: command-line flags that were set; dotted names address nested keys
+//   - DotEnv: .env file variables, read by the Environment stage below the
+//     process environment (an EnvSource)
+//   - Map: in-memory values, mostly for tests and defaults
+//   - HTTP: a remote YAML, JSON or TOML document, fetched with ETag
+//     revalidation and kept on disk (WithFallbackFile) for outages
+//
+// HTTP is a VersionedSource: config.Provider.Watch polls its version next
+// to the profile files and reloads (and validates) the configuration when
+// the remote document changes:
+//
+//	remote := source.HTTP("https://config.example.com/app.yaml",
+//	    source.WithHeader("Authorization", "Bearer "+token),
+//	    source.WithFallbackFile("/var/cache/app/config.yaml"),
+//	    source.WithPollInterval(time.Minute),
+//	    source.WithLogger(logger), // fallback warnings
+//	)
+//
+// HTTP is also an UntrustedSource: the provider resolves only ${env:...}
+// secret references in its values, never ${file:...} or ${cmd:...}.
+//
+// The Profiles and Environment placeholders mark where the provider's own
+// stages run in a precedence list:
+//
+//	config.WithSources(
+//	    source.Profiles,                       // scoped YAML profiles
+//	    source.Optional(source.TOML("app.toml")),
+//	    source.Optional(source.DotEnv(".env")),
+//	    source.Environment,                    // env tags
+//	    source.Flags(nil),                     // flags win over env
+//	)
+package source
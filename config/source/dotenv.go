@@ -0,0 +1,106 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	dotEnvSource struct {
+		path string
+	}
+)
+
+// DotEnv returns an EnvSource reading KEY=VALUE lines from a .env file.
+// Blank lines and # comments are skipped, an "export " prefix is allowed,
+// single-quoted values are literal and double-quoted values support Go
+// escapes (\n, \t, \", ...). Unquoted values end at " #".
+func DotEnv(path string) EnvSource {
+	return &dotEnvSource{path: path}
+}
+
+// Name returns "dotenv:" followed by the file path.
+func (s *dotEnvSource) Name() string {
+	return "dotenv:" + s.path
+}
+
+// Load returns nothing: the variables are read through Environ.
+func (s *dotEnvSource) Load() (map[string]any, error) {
+	return nil, nil
+}
+
+// Environ reads and parses the file. A missing file returns an error
+// wrapping fs.ErrNotExist (see Optional).
+func (s *dotEnvSource) Environ() (map[string]string, error) {
+	content, err := os.ReadFile(filepath.Clean(s.path))
+	if err != nil {
+		return nil, wrapError(s.Name(), err)
+	}
+
+	env, err := parseDotEnv(content)
+	if err != nil {
+		return nil, wrapError(s.Name(), err)
+	}
+
+	return env, nil
+}
+
+func parseDotEnv(content []byte) (map[string]string, error) {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		env[key] = value
+	}
+
+	return env, scanner.Err()
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote in %s", value)
+		}
+
+		return value[1 : end+1], nil
+	case strings.HasPrefix(value, `"`):
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s: %w", value, err)
+		}
+
+		return strconv.Unquote(quoted)
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+
+		return strings.TrimSpace(value), nil
+	}
+}
@@ -0,0 +1,21 @@
+package source_test
+
+import (
+	"fmt"
+
+	"github.com/guionardo/go/config/source"
+)
+
+func ExampleMap() {
+	src := source.Map("defaults", map[string]any{
+		"database": map[string]any{"port": 5432},
+	})
+
+	values, err := src.Load()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(src.Name(), values)
+	// Output: defaults map[database:map[port:5432]]
+}
@@ -0,0 +1,90 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	fileSource struct {
+		format string
+		path   string
+		decode func(content []byte) (map[string]any, error)
+	}
+)
+
+// JSON returns a source reading a JSON object from path.
+func JSON(path string) Source {
+	return &fileSource{format: "json", path: path, decode: decodeJSON}
+}
+
+// TOML returns a source reading a TOML document from path.
+func TOML(path string) Source {
+	return &fileSource{format: "toml", path: path, decode: decodeTOML}
+}
+
+// YAML returns a source reading a YAML mapping from path, for files
+// outside the scoped profiles.
+func YAML(path string) Source {
+	return &fileSource{format: "yaml", path: path, decode: decodeYAML}
+}
+
+// Name returns the format and the file path, e.g. "json:config.json".
+func (s *fileSource) Name() string {
+	return s.format + ":" + s.path
+}
+
+// Load reads and decodes the file. A missing file returns an error
+// wrapping fs.ErrNotExist (see Optional).
+func (s *fileSource) Load() (map[string]any, error) {
+	content, err := os.ReadFile(filepath.Clean(s.path))
+	if err != nil {
+		return nil, wrapError(s.Name(), err)
+	}
+
+	m, err := s.decode(content)
+	if err != nil {
+		return nil, wrapError(s.Name(), err)
+	}
+
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	return normalize(m).(map[string]any), nil //nolint:forcetypeassert
+}
+
+func decodeJSON(content []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var m map[string]any
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func decodeTOML(content []byte) (map[string]any, error) {
+	var m map[string]any
+	if _, err := toml.Decode(string(content), &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func decodeYAML(content []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
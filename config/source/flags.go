@@ -0,0 +1,76 @@
+package source
+
+import (
+	"flag"
+	"strings"
+	"time"
+)
+
+type (
+	flagSource struct {
+		flags *flag.FlagSet
+	}
+)
+
+// Flags returns a source reading the flags explicitly set on the command
+// line (flag.CommandLine if flags is nil); unset flags and their defaults
+// are ignored so they do not mask lower layers. Dotted flag names address
+// nested keys: -database.pool.size=10 sets database.pool.size.
+//
+// The flags must be parsed before the configuration is loaded.
+func Flags(flags *flag.FlagSet) Source {
+	if flags == nil {
+		flags = flag.CommandLine
+	}
+
+	return &flagSource{flags: flags}
+}
+
+// Name returns "flags:" followed by the flag set name.
+func (s *flagSource) Name() string {
+	return "flags:" + s.flags.Name()
+}
+
+// Load returns the set flags as a nested map.
+func (s *flagSource) Load() (map[string]any, error) {
+	m := map[string]any{}
+
+	s.flags.Visit(func(f *flag.Flag) {
+		setPath(m, strings.Split(f.Name, "."), flagValue(f.Value))
+	})
+
+	return m, nil
+}
+
+// flagValue returns the typed value of flag.Getter flags (durations as
+// strings) and the string form of any other flag.Value.
+func flagValue(value flag.Value) any {
+	getter, ok := value.(flag.Getter)
+	if !ok {
+		return value.String()
+	}
+
+	switch v := getter.Get().(type) {
+	case time.Duration:
+		return v.String()
+	case nil:
+		return value.String()
+	default:
+		return normalize(v)
+	}
+}
+
+// setPath stores value under the nested keys of path, creating maps as needed.
+func setPath(m map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[key] = child
+		}
+
+		m = child
+	}
+
+	m[path[len(path)-1]] = value
+}
@@ -0,0 +1,322 @@
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// HTTPOption configures an HTTP source.
+	HTTPOption func(*httpSource)
+
+	// Logger receives the fallback events of the HTTP source; *slog.Logger
+	// and config.Logger implementations satisfy it.
+	Logger interface {
+		Debug(msg string, args ...any)
+		Info(msg string, args ...any)
+		Warn(msg string, args ...any)
+		Error(msg string, args ...any)
+	}
+
+	httpSource struct {
+		url          string
+		client       *http.Client
+		header       http.Header
+		format       string
+		fallbackPath string
+		pollInterval time.Duration
+		logger       Logger
+
+		mu       sync.Mutex
+		etag     string
+		content  []byte
+		version  string
+		polledAt time.Time
+	}
+)
+
+const (
+	// DefaultHTTPTimeout is the request timeout of HTTP sources without
+	// WithHTTPClient.
+	DefaultHTTPTimeout = 10 * time.Second
+
+	fallbackFileMode = 0o600
+)
+
+// ErrHTTPStatus is returned for responses other than 200 and 304.
+var ErrHTTPStatus = errors.New("unexpected HTTP status")
+
+// HTTP returns a source fetching a YAML, JSON or TOML document from url.
+// The format comes from the response Content-Type (YAML when unknown, see
+// WithFormat).
+//
+// Requests after the first send If-None-Match with the last ETag, and a
+// 304 response reuses the last content. When the server is unreachable or
+// fails, the last good content is used: from memory, or from the
+// WithFallbackFile copy written after every successful fetch, so a
+// restart survives an outage.
+//
+// The source is a VersionedSource: with config.Provider.Watch, the
+// endpoint is polled (at most every WithPollInterval) and a new ETag or
+// content triggers a reload and validation.
+//
+// It is also an UntrustedSource: whoever controls the server must not be
+// able to read files or run commands on the client, so only ${env:...}
+// references are resolved in the document.
+func HTTP(url string, opts ...HTTPOption) VersionedSource {
+	s := &httpSource{
+		url:    url,
+		client: &http.Client{Timeout: DefaultHTTPTimeout},
+		header: http.Header{},
+		logger: slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithHTTPClient sets the client used for the requests.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(s *httpSource) {
+		s.client = client
+	}
+}
+
+// WithHeader adds a request header, e.g. an Authorization token.
+func WithHeader(key, value string) HTTPOption {
+	return func(s *httpSource) {
+		s.header.Add(key, value)
+	}
+}
+
+// WithFormat forces the document format ("yaml", "json" or "toml")
+// regardless of the response Content-Type.
+func WithFormat(format string) HTTPOption {
+	return func(s *httpSource) {
+		s.format = format
+	}
+}
+
+// WithFallbackFile sets the file keeping the last good document, read when
+// the server cannot be reached and nothing was fetched yet.
+func WithFallbackFile(path string) HTTPOption {
+	return func(s *httpSource) {
+		s.fallbackPath = path
+	}
+}
+
+// WithPollInterval sets the minimum time between two Version requests;
+// in between, Version returns the last known version.
+func WithPollInterval(interval time.Duration) HTTPOption {
+	return func(s *httpSource) {
+		s.pollInterval = interval
+	}
+}
+
+// WithLogger sets the logger of the fallback events (defaults to
+// slog.Default()).
+func WithLogger(logger Logger) HTTPOption {
+	return func(s *httpSource) {
+		s.logger = logger
+	}
+}
+
+// Name returns "http:" and the URL.
+func (s *httpSource) Name() string {
+	return "http:" + s.url
+}
+
+// Untrusted reports true: the document comes from a remote server.
+func (s *httpSource) Untrusted() bool {
+	return true
+}
+
+// Load fetches the document, falling back to the last good copy.
+func (s *httpSource) Load() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.fetch(); err != nil {
+		if err := s.fallback(err); err != nil {
+			return nil, wrapError(s.Name(), err)
+		}
+	}
+
+	m, err := s.decode(s.content)
+	if err != nil {
+		return nil, wrapError(s.Name(), err)
+	}
+
+	return m, nil
+}
+
+// Version polls the endpoint with the last ETag and returns the ETag (or
+// the content hash when the server sends none). Errors keep the last
+// version, so an outage does not trigger reloads.
+func (s *httpSource) Version() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.version != "" && time.Since(s.polledAt) < s.pollInterval {
+		return s.version, nil
+	}
+
+	if err := s.fetch(); err != nil {
+		return s.version, wrapError(s.Name(), err)
+	}
+
+	return s.version, nil
+}
+
+// fetch requests the document, updating the content and version on 200.
+// Caller MUST hold s.mu.
+func (s *httpSource) fetch() error {
+	request, err := http.NewRequest(http.MethodGet, s.url, nil) //nolint:noctx // bounded by the client timeout
+	if err != nil {
+		return err
+	}
+
+	request.Header = s.header.Clone()
+	if s.etag != "" && s.content != nil {
+		request.Header.Set("If-None-Match", s.etag)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	s.polledAt = time.Now()
+
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("%w %s", ErrHTTPStatus, response.Status)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if s.format == "" {
+		s.format = formatOf(response.Header.Get("Content-Type"))
+	}
+
+	if _, err := s.decode(content); err != nil {
+		return err
+	}
+
+	s.content = content
+	s.etag = response.Header.Get("ETag")
+	s.version = s.etag
+
+	if s.version == "" {
+		sum := sha256.Sum256(content)
+		s.version = hex.EncodeToString(sum[:])
+	}
+
+	if s.fallbackPath != "" {
+		if err := writeFileAtomic(s.fallbackPath, content); err != nil {
+			s.logger.Warn("error writing fallback file", "source", s.Name(), "error", err)
+		}
+	}
+
+	return nil
+}
+
+// fallback keeps the content in memory or reads the fallback file after a
+// failed fetch, returning fetchErr when neither is available.
+// Caller MUST hold s.mu.
+func (s *httpSource) fallback(fetchErr error) error {
+	if s.content != nil {
+		s.logger.Warn("using last fetched configuration", "source", s.Name(), "error", fetchErr)
+		return nil
+	}
+
+	if s.fallbackPath == "" {
+		return fetchErr
+	}
+
+	content, err := os.ReadFile(filepath.Clean(s.fallbackPath))
+	if err != nil {
+		return errors.Join(fetchErr, err)
+	}
+
+	s.logger.Warn("using fallback file", "source", s.Name(), "file", s.fallbackPath, "error", fetchErr)
+	s.content = content
+
+	return nil
+}
+
+func (s *httpSource) decode(content []byte) (map[string]any, error) {
+	decode := decodeYAML
+
+	switch s.format {
+	case "json":
+		decode = decodeJSON
+	case "toml":
+		decode = decodeTOML
+	}
+
+	m, err := decode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	return normalize(m).(map[string]any), nil //nolint:forcetypeassert
+}
+
+// formatOf returns the document format of a Content-Type ("" for YAML or
+// unknown types).
+func formatOf(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return "json"
+	case strings.HasSuffix(mediaType, "toml"):
+		return "toml"
+	default:
+		return ""
+	}
+}
+
+// writeFileAtomic replaces path with content through a temporary file.
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(content); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+
+	if err := errors.Join(tmp.Chmod(fallbackFileMode), tmp.Close()); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
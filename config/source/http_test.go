@@ -0,0 +1,231 @@
+package source_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/source"
+	httptestmock "github.com/guionardo/go/httptest_mock"
+)
+
+// remoteConfig is the document served by newConfigServer, changed by the
+// tests between requests.
+type remoteConfig struct {
+	mu          sync.Mutex
+	status      int
+	contentType string
+	etag        string
+	body        string
+	ifNoneMatch []string
+}
+
+func (c *remoteConfig) set(status int, etag, body string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.status, c.etag, c.body = status, etag, body
+}
+
+func (c *remoteConfig) conditionalRequests() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ifNoneMatch
+}
+
+func newConfigServer(t *testing.T, config *remoteConfig) string {
+	t.Helper()
+
+	server, _ := httptestmock.SetupServer(t, httptestmock.WithoutLog(), httptestmock.WithRequests(
+		httptestmock.NewMock(http.MethodGet, "/config").WithResponseStatus(http.StatusOK).WithCustomHandler(
+			func(_ httptestmock.Mocker, w http.ResponseWriter, r *http.Request) {
+				config.mu.Lock()
+				defer config.mu.Unlock()
+
+				if match := r.Header.Get("If-None-Match"); match != "" {
+					config.ifNoneMatch = append(config.ifNoneMatch, match)
+					if match == config.etag && config.status == http.StatusOK {
+						w.WriteHeader(http.StatusNotModified)
+						return
+					}
+				}
+
+				if config.contentType != "" {
+					w.Header().Set("Content-Type", config.contentType)
+				}
+
+				if config.etag != "" {
+					w.Header().Set("ETag", config.etag)
+				}
+
+				w.WriteHeader(config.status)
+				_, _ = w.Write([]byte(config.body))
+			}),
+	))
+
+	return server.URL + "/config"
+}
+
+func TestHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes_by_content_type", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{contentType: "application/json"}
+		config.set(http.StatusOK, `"v1"`, `{"name":"remote","port":8080}`)
+
+		src := source.HTTP(newConfigServer(t, config))
+		got, err := src.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "remote", "port": 8080}, got)
+		assert.Contains(t, src.Name(), "http:")
+	})
+
+	t.Run("not_modified_reuses_content", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: remote\n")
+
+		src := source.HTTP(newConfigServer(t, config))
+		_, err := src.Load()
+		require.NoError(t, err)
+
+		got, err := src.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "remote"}, got)
+		assert.Equal(t, []string{`"v1"`}, config.conditionalRequests())
+	})
+
+	t.Run("version_follows_etag", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: first\n")
+
+		src := source.HTTP(newConfigServer(t, config))
+		version, err := src.Version()
+		require.NoError(t, err)
+		assert.Equal(t, `"v1"`, version)
+
+		config.set(http.StatusOK, `"v2"`, "name: second\n")
+		version, err = src.Version()
+		require.NoError(t, err)
+		assert.Equal(t, `"v2"`, version)
+
+		got, err := src.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "second"}, got)
+	})
+
+	t.Run("version_hashes_content_without_etag", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, "", "name: first\n")
+
+		src := source.HTTP(newConfigServer(t, config))
+		first, err := src.Version()
+		require.NoError(t, err)
+		assert.NotEmpty(t, first)
+
+		config.set(http.StatusOK, "", "name: second\n")
+		second, err := src.Version()
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("poll_interval_limits_requests", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: first\n")
+
+		src := source.HTTP(newConfigServer(t, config), source.WithPollInterval(time.Hour))
+		_, err := src.Version()
+		require.NoError(t, err)
+
+		config.set(http.StatusOK, `"v2"`, "name: second\n")
+		version, err := src.Version()
+		require.NoError(t, err)
+		assert.Equal(t, `"v1"`, version)
+		assert.Empty(t, config.conditionalRequests())
+	})
+
+	t.Run("keeps_last_content_on_error", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: remote\n")
+
+		src := source.HTTP(newConfigServer(t, config))
+		_, err := src.Load()
+		require.NoError(t, err)
+
+		config.set(http.StatusInternalServerError, "", "")
+		got, err := src.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "remote"}, got)
+
+		version, err := src.Version()
+		require.ErrorIs(t, err, source.ErrHTTPStatus)
+		assert.Equal(t, `"v1"`, version)
+	})
+
+	t.Run("falls_back_to_file", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: remote\n")
+
+		url := newConfigServer(t, config)
+		fallback := filepath.Join(t.TempDir(), "remote.yaml")
+
+		_, err := source.HTTP(url, source.WithFallbackFile(fallback)).Load()
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(fallback)
+		require.NoError(t, err)
+		assert.Equal(t, "name: remote\n", string(content))
+
+		config.set(http.StatusServiceUnavailable, "", "")
+		got, err := source.HTTP(url, source.WithFallbackFile(fallback)).Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "remote"}, got)
+	})
+
+	t.Run("error_without_fallback", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusNotFound, "", "")
+
+		_, err := source.HTTP(newConfigServer(t, config),
+			source.WithFallbackFile(filepath.Join(t.TempDir(), "missing.yaml"))).Load()
+		require.ErrorIs(t, err, source.ErrHTTPStatus)
+	})
+
+	t.Run("invalid_content_is_not_cached", func(t *testing.T) {
+		t.Parallel()
+
+		config := &remoteConfig{}
+		config.set(http.StatusOK, `"v1"`, "name: remote\n")
+
+		src := source.HTTP(newConfigServer(t, config), source.WithHeader("Authorization", "Bearer token"))
+		_, err := src.Load()
+		require.NoError(t, err)
+
+		config.set(http.StatusOK, `"v2"`, ":::: invalid ::::")
+		got, err := src.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"name": "remote"}, got)
+	})
+}
@@ -0,0 +1,203 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"reflect"
+)
+
+type (
+	// Source is a configuration layer that loads a map with the same shape
+	// as the YAML profiles (keys are the yaml field names).
+	Source interface {
+		// Name identifies the source in logs and errors.
+		Name() string
+		// Load reads the source. It is called on every (re)load.
+		Load() (map[string]any, error)
+	}
+
+	// EnvSource is a Source of environment variables, such as a .env file.
+	// Its variables are read by the Environment stage, below the process
+	// environment: a variable set in the process always wins.
+	EnvSource interface {
+		Source
+		// Environ returns the variables by name.
+		Environ() (map[string]string, error)
+	}
+
+	// VersionedSource is a Source that can tell cheaply whether its content
+	// changed, such as HTTP: Provider.Watch polls it next to the profile
+	// files and reloads when the version changes.
+	VersionedSource interface {
+		Source
+		// Version returns a token that changes with the content.
+		Version() (string, error)
+	}
+
+	// UntrustedSource is a Source whose content is controlled by someone
+	// else than the application, such as a remote HTTP document: the
+	// provider resolves only ${env:...} secret references in its values and
+	// keeps any other ${scheme:...} reference as literal text.
+	UntrustedSource interface {
+		Source
+		// Untrusted reports whether the values must be kept away from the
+		// file, cmd and custom secret resolvers.
+		Untrusted() bool
+	}
+
+	// stage is a placeholder for a stage run by the provider itself.
+	stage string
+
+	mapSource struct {
+		name   string
+		values map[string]any
+	}
+
+	optionalSource struct {
+		Source
+	}
+
+	optionalEnvSource struct {
+		optionalSource
+	}
+)
+
+var (
+	// Profiles marks the position of the provider's scoped YAML profiles
+	// in a precedence list.
+	Profiles Source = stage("profiles")
+
+	// Environment marks the position of the environment variables (struct
+	// `env` tags, plus any EnvSource) in a precedence list.
+	Environment Source = stage("environment")
+)
+
+// Name returns the stage name.
+func (s stage) Name() string {
+	return string(s)
+}
+
+// Load returns nothing: stages are run by the provider.
+func (s stage) Load() (map[string]any, error) {
+	return nil, nil
+}
+
+// Map returns an in-memory source, mostly useful for tests and defaults.
+// The map is copied on every Load.
+func Map(name string, values map[string]any) Source {
+	return &mapSource{name: name, values: values}
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) Load() (map[string]any, error) {
+	return maps.Clone(s.values), nil
+}
+
+// Optional wraps a file source so that a missing file loads as empty
+// instead of failing.
+func Optional(src Source) Source {
+	if _, ok := src.(EnvSource); ok {
+		return optionalEnvSource{optionalSource{Source: src}}
+	}
+
+	return optionalSource{Source: src}
+}
+
+// IsUntrusted reports whether src is an UntrustedSource that reports
+// itself untrusted.
+func IsUntrusted(src Source) bool {
+	untrusted, ok := src.(UntrustedSource)
+
+	return ok && untrusted.Untrusted()
+}
+
+// Untrusted reports whether the wrapped source is untrusted.
+func (s optionalSource) Untrusted() bool {
+	return IsUntrusted(s.Source)
+}
+
+// Load returns an empty map when the wrapped source's file does not exist.
+func (s optionalSource) Load() (map[string]any, error) {
+	m, err := s.Source.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]any{}, nil
+	}
+
+	return m, err
+}
+
+// Environ returns no variables when the wrapped source's file does not exist.
+func (s optionalEnvSource) Environ() (map[string]string, error) {
+	env, err := s.Source.(EnvSource).Environ() //nolint:forcetypeassert // checked in Optional
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+
+	return env, err
+}
+
+// normalize converts decoded values in place to the types yaml.v3 produces
+// (int, float64, map[string]any, []any), so that merger.MergeMaps sees the
+// same types across formats and layers override each other.
+func normalize(v any) any { //nolint:cyclop
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return normalize(i)
+		}
+
+		f, _ := value.Float64()
+
+		return f
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return normalizeInt(value)
+	case float32:
+		return float64(value)
+	case map[string]any:
+		for k, item := range value {
+			value[k] = normalize(item)
+		}
+	case []map[string]any:
+		items := make([]any, len(value))
+		for i, item := range value {
+			items[i] = normalize(item)
+		}
+
+		return items
+	case []any:
+		for i, item := range value {
+			value[i] = normalize(item)
+		}
+	}
+
+	return v
+}
+
+// normalizeInt returns integers as int when they fit, like yaml.v3.
+func normalizeInt(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.CanInt() {
+		if i := rv.Int(); i >= math.MinInt && i <= math.MaxInt {
+			return int(i)
+		}
+
+		return v
+	}
+
+	if u := rv.Uint(); u <= math.MaxInt {
+		return int(u)
+	}
+
+	return rv.Uint()
+}
+
+func wrapError(name string, err error) error {
+	return fmt.Errorf("config/source: %s: %w", name, err)
+}
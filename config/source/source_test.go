@@ -0,0 +1,184 @@
+package source_test
+
+import (
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/source"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestFileSources(t *testing.T) {
+	t.Parallel()
+
+	want := map[string]any{
+		"name": "app",
+		"database": map[string]any{
+			"port":  5432,
+			"ratio": 0.5,
+			"hosts": []any{"a", "b"},
+		},
+	}
+
+	for name, src := range map[string]source.Source{
+		"json": source.JSON(writeFile(t, "app.json",
+			`{"name":"app","database":{"port":5432,"ratio":0.5,"hosts":["a","b"]}}`)),
+		"toml": source.TOML(writeFile(t, "app.toml",
+			"name = \"app\"\n[database]\nport = 5432\nratio = 0.5\nhosts = [\"a\", \"b\"]\n")),
+		"yaml": source.YAML(writeFile(t, "app.yaml",
+			"name: app\ndatabase:\n  port: 5432\n  ratio: 0.5\n  hosts: [a, b]\n")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := src.Load()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Contains(t, src.Name(), name+":")
+		})
+	}
+
+	t.Run("invalid_content", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := source.JSON(writeFile(t, "bad.json", "{")).Load()
+		require.Error(t, err)
+	})
+
+	t.Run("empty_yaml", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := source.YAML(writeFile(t, "empty.yaml", "")).Load()
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := source.JSON(missing).Load()
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	got, err := source.Optional(source.JSON(missing)).Load()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	envSource, ok := source.Optional(source.DotEnv(missing)).(source.EnvSource)
+	require.True(t, ok)
+
+	env, err := envSource.Environ()
+	require.NoError(t, err)
+	assert.Empty(t, env)
+
+	_, ok = source.Optional(source.JSON(missing)).(source.EnvSource)
+	assert.False(t, ok)
+}
+
+func TestIsUntrusted(t *testing.T) {
+	t.Parallel()
+
+	remote := source.HTTP("https://config.example.com/app.yaml")
+
+	assert.True(t, source.IsUntrusted(remote))
+	assert.True(t, source.IsUntrusted(source.Optional(remote)))
+	assert.False(t, source.IsUntrusted(source.YAML("app.yaml")))
+	assert.False(t, source.IsUntrusted(source.Optional(source.YAML("app.yaml"))))
+	assert.False(t, source.IsUntrusted(source.Map("defaults", nil)))
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{"name": "test"}
+	src := source.Map("defaults", values)
+
+	got, err := src.Load()
+	require.NoError(t, err)
+	assert.Equal(t, values, got)
+	assert.Equal(t, "defaults", src.Name())
+
+	got["name"] = "changed"
+	assert.Equal(t, "test", values["name"])
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.String("name", "default-name", "")
+	flags.Int64("database.pool.size", 1, "")
+	flags.Duration("database.timeout", time.Second, "")
+	flags.Bool("debug", false, "")
+	require.NoError(t, flags.Parse([]string{"-database.pool.size=10", "-database.timeout=3s", "-debug"}))
+
+	got, err := source.Flags(flags).Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"database": map[string]any{
+			"pool":    map[string]any{"size": 10},
+			"timeout": "3s",
+		},
+		"debug": true,
+	}, got, "unset flags are not loaded")
+	assert.Equal(t, "flags:app", source.Flags(flags).Name())
+}
+
+func TestDotEnv(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, ".env", `
+# comment
+APP_NAME=app # trailing comment
+export APP_PORT=8080
+APP_SINGLE='literal \n #value'
+APP_DOUBLE="line\nbreak"
+APP_EMPTY=
+`)
+
+	src := source.DotEnv(path)
+
+	env, err := src.Environ()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"APP_NAME":   "app",
+		"APP_PORT":   "8080",
+		"APP_SINGLE": `literal \n #value`,
+		"APP_DOUBLE": "line\nbreak",
+		"APP_EMPTY":  "",
+	}, env)
+
+	m, err := src.Load()
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	for name, content := range map[string]string{
+		"missing_equals":   "APP_NAME",
+		"unterminated":     "APP_NAME='value",
+		"invalid_escaping": `APP_NAME="value`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := source.DotEnv(writeFile(t, ".env", content)).Environ()
+			require.Error(t, err)
+		})
+	}
+}
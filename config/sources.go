@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/source"
+	"gopkg.in/yaml.v3"
+)
+
+// WithSources adds configuration layers, lowest precedence first. Map
+// layers are deep-merged with merger.Merge (see WithMergeOptions) and
+// decoded like the YAML profiles (keys are yaml field names).
+//
+// By default the order is: source.Profiles, the given sources,
+// source.Environment. Place the source.Profiles and source.Environment
+// placeholders in the list to change where those stages run, e.g. to let
+// flags override environment variables:
+//
+//	config.WithSources(source.Profiles, source.JSON("app.json"),
+//	    source.Environment, source.Flags(nil))
+func WithSources(sources ...source.Source) providerOption {
+	return func(p *provider) {
+		p.sources = append(p.sources, sources...)
+	}
+}
+
+// WithMergeOptions sets how the map layers are merged, e.g. to append
+// lists or merge them by key, or to let an explicit null delete a key:
+//
+//	config.WithMergeOptions(merger.WithNullDelete(),
+//	    merger.WithListStrategy(merger.ListMergeByKey("name"), "servers"))
+func WithMergeOptions(opts ...merger.Option) providerOption {
+	return func(p *provider) {
+		p.mergeOptions = append(p.mergeOptions, opts...)
+	}
+}
+
+// layers returns the configuration layers in precedence order, adding the
+// profiles and environment stages at their default positions when missing.
+func (p *provider) layers() []source.Source {
+	var hasProfiles, hasEnvironment bool
+
+	for _, layer := range p.sources {
+		hasProfiles = hasProfiles || layer == source.Profiles
+		hasEnvironment = hasEnvironment || layer == source.Environment
+	}
+
+	layers := make([]source.Source, 0, len(p.sources)+2)
+	if !hasProfiles {
+		layers = append(layers, source.Profiles)
+	}
+
+	layers = append(layers, p.sources...)
+	if !hasEnvironment {
+		layers = append(layers, source.Environment)
+	}
+
+	return layers
+}
+
+// envLookup returns the variable lookup of the environment stage: the
+// process environment first, then the EnvSource layers (later layers win).
+// It also returns the sorted names of all the variables it can see.
+func envLookup(layers []source.Source) (func(string) (string, bool), []string, error) {
+	env := map[string]string{}
+
+	for _, layer := range layers {
+		envSource, ok := layer.(source.EnvSource)
+		if !ok {
+			continue
+		}
+
+		vars, err := envSource.Environ()
+		if err != nil {
+			return os.LookupEnv, environNames(nil), fmt.Errorf("source %s: %w", layer.Name(), err)
+		}
+
+		maps.Copy(env, vars)
+	}
+
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+
+		value, ok := env[name]
+
+		return value, ok
+	}, environNames(env), nil
+}
+
+// environNames returns the sorted, unique names of the process environment
+// and env.
+func environNames(env map[string]string) []string {
+	names := slices.Collect(maps.Keys(env))
+
+	for _, entry := range os.Environ() {
+		if name, _, _ := strings.Cut(entry, "="); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// decodeMap decodes a merged layer map over configuration.
+func decodeMap(configuration any, m map[string]any) error {
+	content, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(content, configuration)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/source"
+)
+
+func newSourcesProvider(t *testing.T, sources ...source.Source) *Provider[testConfig] {
+	t.Helper()
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"),
+		[]byte("name: profile\nversion: 1\nnested:\n  tags: profile\n"), 0600))
+
+	return NewProvider[testConfig](
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("default"),
+		WithSources(sources...),
+	)
+}
+
+func TestWithSources(t *testing.T) {
+	t.Run("default_precedence", func(t *testing.T) {
+		t.Setenv("TESTCFG_VERSION", "3")
+
+		provider := newSourcesProvider(t,
+			source.Map("low", map[string]any{"version": 2, "secret": "low"}),
+			source.Map("high", map[string]any{"secret": "high", "nested": map[string]any{"enabled": true}}),
+		)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "profile", cfg.Name)
+		assert.Equal(t, "profile", cfg.Nested.Tags)
+		assert.Equal(t, 3, cfg.Version, "environment wins by default")
+		assert.Equal(t, "high", cfg.Secret, "later sources win")
+		assert.True(t, cfg.Nested.Enabled)
+	})
+
+	t.Run("custom_precedence", func(t *testing.T) {
+		t.Setenv("TESTCFG_NAME", "env")
+		t.Setenv("TESTCFG_VERSION", "3")
+
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Int("version", 0, "")
+		require.NoError(t, flags.Parse([]string{"-version=4"}))
+
+		provider := newSourcesProvider(t,
+			source.Environment,
+			source.Profiles,
+			source.Flags(flags),
+		)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "profile", cfg.Name, "profiles override the environment")
+		assert.Equal(t, 4, cfg.Version, "flags override everything")
+	})
+
+	t.Run("dotenv_below_process_environment", func(t *testing.T) {
+		t.Setenv("TESTCFG_NAME", "env")
+
+		dotEnv := path.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(dotEnv, []byte("TESTCFG_NAME=dotenv\nTESTCFG_VERSION=5\n"), 0600))
+
+		provider := newSourcesProvider(t, source.DotEnv(dotEnv))
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "env", cfg.Name)
+		assert.Equal(t, 5, cfg.Version)
+	})
+
+	t.Run("source_error", func(t *testing.T) {
+		provider := newSourcesProvider(t, source.JSON(path.Join(t.TempDir(), "missing.json")))
+
+		cfg, err := provider.GetConfiguration()
+		require.ErrorContains(t, err, "source json:")
+		assert.Equal(t, "profile", cfg.Name, "other layers still load")
+	})
+}
+
+func TestLayers(t *testing.T) {
+	t.Parallel()
+
+	json := source.JSON("app.json")
+
+	p := &provider{sources: []source.Source{json}}
+	assert.Equal(t, []source.Source{source.Profiles, json, source.Environment}, p.layers())
+
+	p = &provider{sources: []source.Source{source.Environment, json, source.Profiles}}
+	assert.Equal(t, []source.Source{source.Environment, json, source.Profiles}, p.layers())
+}
+
+func TestWithMergeOptions(t *testing.T) {
+	t.Parallel()
+
+	const profile = "name: app\nport: 8080\nservers:\n  - host: a\n  - host: b\n"
+
+	overrides := source.Map("overrides", map[string]any{
+		"servers": []any{map[string]any{"host": "c"}},
+		"port":    nil,
+	})
+
+	provider := newStrictProvider(t, profile, WithSources(overrides),
+		WithMergeOptions(merger.WithListStrategy(merger.ListAppend), merger.WithNullDelete()))
+
+	cfg, err := provider.GetConfiguration()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Port)
+	require.Len(t, cfg.Servers, 3)
+	assert.Equal(t, "c", cfg.Servers[2].Host)
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/guionardo/go/config/environment"
+	"github.com/guionardo/go/config/merger"
+)
+
+type (
+	// StrictMode selects how the provider reacts to configuration input it
+	// cannot apply: unknown keys, unknown prefixed environment variables and
+	// values skipped by the merge because of a type mismatch.
+	StrictMode uint8
+)
+
+const (
+	// StrictOff ignores unknown input (the default).
+	StrictOff StrictMode = iota
+	// StrictWarn logs a warning for each problem and loads the configuration.
+	StrictWarn
+	// StrictFail logs the problems and fails loading with an error wrapping
+	// ErrUnknownKey, ErrUnknownEnv or ErrTypeMismatch.
+	StrictFail
+)
+
+var (
+	// ErrUnknownKey is a profile or source key that matches no field.
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrUnknownEnv is an environment variable with the env prefix that
+	// matches no field.
+	ErrUnknownEnv = errors.New("unknown environment variable")
+	// ErrTypeMismatch is a value skipped by the merge because its type
+	// differs from the value it would override.
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// WithStrict makes the provider report configuration input that would
+// otherwise be silently ignored:
+//   - profile and source keys that match no field (typos)
+//   - environment variables starting with the WithEnvPrefix prefix that
+//     match no field (only checked when a prefix is set)
+//   - values skipped by the merge because their type differs from the
+//     value they override (e.g. a string over an int)
+//
+// With StrictFail, GetConfiguration returns the errors without storing the
+// configuration and Watch keeps the current one.
+func WithStrict(mode StrictMode) providerOption {
+	return func(p *provider) {
+		p.strict = mode
+	}
+}
+
+// strictErrors logs the strict mode violations and returns them when the
+// mode is StrictFail.
+func (p *provider) strictErrors(violations []error) []error {
+	if p.strict == StrictOff || len(violations) == 0 {
+		return nil
+	}
+
+	for _, violation := range violations {
+		if p.strict == StrictFail {
+			p.log().Error("strict configuration", "error", violation)
+		} else {
+			p.log().Warn("strict configuration", "error", violation)
+		}
+	}
+
+	if p.strict == StrictFail {
+		return violations
+	}
+
+	return nil
+}
+
+// isStrictError reports whether err holds a strict mode violation.
+func isStrictError(err error) bool {
+	return errors.Is(err, ErrUnknownKey) || errors.Is(err, ErrUnknownEnv) || errors.Is(err, ErrTypeMismatch)
+}
+
+// unknownKeys returns an error for each key of the layer that matches no
+// field of t.
+func (l mapLayer) unknownKeys(t reflect.Type) []error {
+	var errs []error
+
+	walkUnknownKeys(t, l.values, nil, func(yamlPath []string) {
+		errs = append(errs, fmt.Errorf("%w %q (%s)", ErrUnknownKey, strings.Join(yamlPath, "."), l.origin(yamlPath)))
+	})
+
+	return errs
+}
+
+// walkUnknownKeys visits the key paths of m that match no field of t,
+// following nested structs, slices and maps of structs.
+func walkUnknownKeys(t reflect.Type, m map[string]any, parent []string, visit func(path []string)) {
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		path := append(parent[:len(parent):len(parent)], key)
+
+		field, ok := yamlField(t, key)
+		if !ok {
+			visit(path)
+			continue
+		}
+
+		walkUnknownValue(field.Type, m[key], path, visit)
+	}
+}
+
+// walkUnknownValue checks a value decoded into a field of type t.
+func walkUnknownValue(t reflect.Type, value any, path []string, visit func(path []string)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := value.(map[string]any); ok && isFieldStruct(t) {
+			walkUnknownKeys(t, m, path, visit)
+		}
+	case reflect.Map:
+		if m, ok := value.(map[string]any); ok {
+			for _, key := range slices.Sorted(maps.Keys(m)) {
+				walkUnknownValue(t.Elem(), m[key], append(path[:len(path):len(path)], key), visit)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := value.([]any); ok {
+			for i, item := range items {
+				walkUnknownValue(t.Elem(), item, append(path[:len(path):len(path)], strconv.Itoa(i)), visit)
+			}
+		}
+	}
+}
+
+// typeMismatches returns an error for each value skipped by the merge of
+// layers into t. The values of `safe` fields and of the masked field paths
+// (encrypted profiles, resolved secrets) are masked, and maps and lists
+// are reported by type only, as they may hold such fields.
+func typeMismatches(t reflect.Type, layers []mapLayer, conflicts []merger.Conflict, masked []string) []error {
+	errs := make([]error, 0, len(conflicts))
+	mask := maskedFields(masked)
+
+	for _, conflict := range conflicts {
+		outcome := "ignored, keeps"
+		if conflict.Overridden {
+			outcome = "overrides"
+		}
+
+		hide := mask(goFieldPath(t, conflict.Path), isSafeYAMLPath(t, conflict.Path))
+
+		errs = append(errs, fmt.Errorf("%w at %q: %s %s %s (%s)",
+			ErrTypeMismatch, strings.Join(conflict.Path, "."), conflictValue(conflict.Value, hide),
+			outcome, conflictValue(conflict.Current, hide), layers[conflict.Layer].origin(conflict.Path)))
+	}
+
+	return errs
+}
+
+// conflictValue formats a merge conflict value as its type and, for a
+// scalar that is not hidden, its value.
+func conflictValue(value any, hide bool) string {
+	switch {
+	case value == nil:
+		return "null"
+	case hide:
+		return fmt.Sprintf("%T %s", value, maskedValue)
+	}
+
+	switch reflect.TypeOf(value).Kind() { //nolint:exhaustive
+	case reflect.Map, reflect.Slice:
+		return fmt.Sprintf("%T", value)
+	default:
+		return fmt.Sprintf("%T %v", value, value)
+	}
+}
+
+// unknownEnv returns an error for each variable name starting with prefix
+// that Parse would not read into t.
+func unknownEnv(t reflect.Type, prefix string, names []string, opts []environment.Option) []error {
+	if prefix == "" {
+		return nil
+	}
+
+	var (
+		known    = map[string]bool{}
+		repeated []environment.Variable
+	)
+
+	for _, variable := range environment.Variables(t, opts...) {
+		if variable.Repeated() {
+			repeated = append(repeated, variable)
+		} else {
+			known[variable.Name] = true
+		}
+	}
+
+	var errs []error
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || known[name] {
+			continue
+		}
+
+		if !slices.ContainsFunc(repeated, func(v environment.Variable) bool { return v.Match(name) }) {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownEnv, name))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/merger"
+	"github.com/guionardo/go/config/source"
+)
+
+type strictConfig struct {
+	Name    string `yaml:"name" validate:"required"`
+	Port    int    `yaml:"port"`
+	Servers []struct {
+		Host string `yaml:"host"`
+	} `yaml:"servers"`
+	Limits map[string]struct {
+		Max int `yaml:"max"`
+	} `yaml:"limits"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+func newStrictProvider(t *testing.T, profile string, options ...providerOption) *Provider[strictConfig] {
+	t.Helper()
+
+	tmp := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"), []byte(profile), 0600))
+
+	return NewProvider[strictConfig](append([]providerOption{
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("default"),
+	}, options...)...)
+}
+
+func TestWithStrict(t *testing.T) {
+	const profile = "name: app\nport: 8080\nservers:\n  - host: a\n    hots: b\nnmae: typo\n"
+
+	t.Run("off_ignores_unknown_keys", func(t *testing.T) {
+		provider := newStrictProvider(t, profile)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "app", cfg.Name)
+	})
+
+	t.Run("warn_loads_configuration", func(t *testing.T) {
+		provider := newStrictProvider(t, profile, WithStrict(StrictWarn),
+			WithSources(source.Map("override", map[string]any{"port": "http"})))
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Port)
+	})
+
+	t.Run("fail_reports_unknown_keys_and_mismatches", func(t *testing.T) {
+		provider := newStrictProvider(t, profile, WithStrict(StrictFail),
+			WithSources(source.Map("override", map[string]any{"port": "http"})))
+
+		_, err := provider.GetConfiguration()
+		require.ErrorIs(t, err, ErrUnknownKey)
+		require.ErrorIs(t, err, ErrTypeMismatch)
+		assert.Contains(t, err.Error(), `unknown key "nmae" (profile `)
+		assert.Contains(t, err.Error(), `default.yml:6)`)
+		assert.Contains(t, err.Error(), `unknown key "servers.0.hots"`)
+		assert.Contains(t, err.Error(), `type mismatch at "port": string http ignored, keeps int 8080 (override)`)
+		assert.False(t, provider.loaded)
+	})
+
+	t.Run("fail_reports_unknown_prefixed_env", func(t *testing.T) {
+		t.Setenv("STRICTCFG_NAME", "env")
+		t.Setenv("STRICTCFG_NMAE", "typo")
+
+		provider := newStrictProvider(t, "name: app\n", WithStrict(StrictFail),
+			WithEnvPrefix("STRICTCFG_"), WithEnvAutoNaming())
+
+		_, err := provider.GetConfiguration()
+		require.ErrorIs(t, err, ErrUnknownEnv)
+		assert.Contains(t, err.Error(), `"STRICTCFG_NMAE"`)
+		assert.NotContains(t, err.Error(), `"STRICTCFG_NAME"`)
+	})
+
+	t.Run("fail_accepts_indexed_and_keyed_env", func(t *testing.T) {
+		t.Setenv("STRICTIDX_SERVERS_1_HOST", "b")
+		t.Setenv("STRICTIDX_LIMITS_API_MAX", "5")
+		t.Setenv("STRICTIDX_LIMITS_BATCH_JOBS_MAX", "7")
+
+		provider := newStrictProvider(t, "name: app\nservers:\n  - host: a\nlimits:\n  api:\n    max: 3\n",
+			WithStrict(StrictFail), WithEnvPrefix("STRICTIDX_"), WithEnvAutoNaming())
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		require.Len(t, cfg.Servers, 2)
+		assert.Equal(t, "a", cfg.Servers[0].Host)
+		assert.Equal(t, "b", cfg.Servers[1].Host)
+		assert.Equal(t, 5, cfg.Limits["api"].Max)
+		assert.Equal(t, 7, cfg.Limits["batch_jobs"].Max)
+
+		t.Setenv("STRICTIDX_SERVERS_X_HOST", "typo")
+
+		_, err = newStrictProvider(t, "name: app\n", WithStrict(StrictFail),
+			WithEnvPrefix("STRICTIDX_"), WithEnvAutoNaming()).GetConfiguration()
+		require.ErrorIs(t, err, ErrUnknownEnv)
+		assert.Contains(t, err.Error(), `"STRICTIDX_SERVERS_X_HOST"`)
+	})
+
+	t.Run("fail_passes_clean_configuration", func(t *testing.T) {
+		provider := newStrictProvider(t, "name: app\nlimits:\n  api:\n    max: 3\nlabels:\n  any: thing\n",
+			WithStrict(StrictFail))
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.Limits["api"].Max)
+	})
+}
+
+func TestWalkUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"name":    "app",
+		"servers": []any{map[string]any{"host": "a"}, map[string]any{"port": 1}},
+		"limits":  map[string]any{"api": map[string]any{"max": 1, "min": 0}},
+		"labels":  map[string]any{"free": "form"},
+		"extra":   map[string]any{"nested": true},
+	}
+
+	var unknown []string
+
+	walkUnknownKeys(reflect.TypeFor[strictConfig](), values, nil, func(path []string) {
+		unknown = append(unknown, strings.Join(path, "."))
+	})
+
+	assert.Equal(t, []string{"extra", "limits.api.min", "servers.1.port"}, unknown)
+}
+
+func TestTypeMismatchesMasking(t *testing.T) {
+	t.Parallel()
+
+	type maskedConfig struct {
+		Token    string `yaml:"token" safe:"true"`
+		Password string `yaml:"password"`
+		Port     int    `yaml:"port"`
+		Database struct {
+			Host string `yaml:"host"`
+			Key  string `yaml:"key" safe:"true"`
+		} `yaml:"database"`
+	}
+
+	layers := []mapLayer{{source: OriginProfile}, {source: "override"}}
+	conflicts := []merger.Conflict{
+		{Path: []string{"token"}, Layer: 1, Current: "s3cr3t-token", Value: 42},
+		{Path: []string{"password"}, Layer: 1, Current: "hunter2", Value: 42},
+		{Path: []string{"port"}, Layer: 1, Current: 8080, Value: "http"},
+		{Path: []string{"database"}, Layer: 1, Current: map[string]any{"key": "db-key"}, Value: "postgres://x"},
+	}
+
+	errs := typeMismatches(reflect.TypeFor[maskedConfig](), layers, conflicts, []string{"Password"})
+	require.Len(t, errs, 4)
+
+	text := errors.Join(errs...).Error()
+	for _, secret := range []string{"s3cr3t-token", "hunter2", "db-key"} {
+		assert.NotContains(t, text, secret)
+	}
+
+	assert.Equal(t, `type mismatch at "token": int ******** ignored, keeps string ******** (override)`,
+		errs[0].Error())
+	assert.Contains(t, errs[1].Error(), "keeps string ********", "secret paths are masked")
+	assert.Contains(t, errs[2].Error(), "string http ignored, keeps int 8080 (override)")
+	assert.Contains(t, errs[3].Error(), "keeps map[string]interface {} (override)", "maps are reported by type")
+}
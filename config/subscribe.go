@@ -0,0 +1,60 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// Subscribe registers fn to be called with the old and new configuration
+// every time it changes, whether by UpdateConfiguration or by a Watch reload.
+// Callbacks run synchronously, in registration order, on the goroutine that
+// performed the change and after the new configuration is visible.
+// Use Diff(old, new) to react only to relevant fields.
+//
+// The returned function unsubscribes fn; it is safe to call more than once.
+func (p *Provider[T]) Subscribe(fn func(old, new T)) (unsubscribe func()) {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+
+	if p.subscribers == nil {
+		p.subscribers = make(map[uint64]func(old, new T))
+	}
+
+	id := p.nextSubID
+	p.nextSubID++
+	p.subscribers[id] = fn
+
+	return func() {
+		p.watchLock.Lock()
+		defer p.watchLock.Unlock()
+
+		delete(p.subscribers, id)
+	}
+}
+
+// notify logs the changed fields (masking secretPaths) and delivers the
+// change to subscribers and watchers. Watcher channels never block the caller.
+func (p *Provider[T]) notify(change Change[T], secretPaths []string) {
+	p.log().Info("configuration changed", "changes", change.Diff().masked(secretPaths))
+
+	p.watchLock.Lock()
+	ids := slices.Sorted(maps.Keys(p.subscribers))
+	callbacks := make([]func(old, new T), 0, len(ids))
+
+	for _, id := range ids {
+		callbacks = append(callbacks, p.subscribers[id])
+	}
+
+	for ch := range p.watchers {
+		select {
+		case ch <- change:
+		default:
+			p.log().Warn("configuration watcher is not keeping up, dropping change")
+		}
+	}
+	p.watchLock.Unlock()
+
+	for _, callback := range callbacks {
+		callback(change.Old, change.New)
+	}
+}
@@ -8,5 +8,20 @@
 //	err := validation.Validate(myStruct)
 //
 // Types implementing the Validator interface can provide custom
-// validation logic beyond struct tags.
+// validation logic beyond struct tags; Validate returns the problems of
+// both, joined.
+//
+// Tag rule failures are returned as Errors, one FieldError per failed rule
+// with the field path and a readable message, in English or, with
+// WithLanguage / SetDefaultLanguage, Brazilian Portuguese:
+//
+//	err := validation.Validate(cfg, validation.WithLanguage(validation.PortugueseBR))
+//	// Database.Host é obrigatório
+//
+// Besides the go-playground rules, "cpf" and "cnpj" validate Brazilian
+// documents (br_docs). Custom rules are registered at program start:
+//   - RegisterTag: a tag rule with its message
+//   - RegisterAlias: a tag standing for a list of rules
+//   - RegisterStructRule: a cross-field rule for a struct type
+//   - RegisterMessage: a message for a tag, in any language
 package validation
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type (
+	// FieldError is a failed tag rule.
+	FieldError struct {
+		// Path is the dotted Go field path, e.g. "Database.Pool.Size".
+		Path string
+		// Tag is the failed rule, e.g. "required" or "min".
+		Tag string
+		// Param is the rule parameter, e.g. "3" for min=3.
+		Param string
+		// Value is the field value.
+		Value any
+		// Message is the readable message, e.g. "Database.Pool.Size must be at least 3".
+		Message string
+	}
+
+	// Errors are the failed tag rules of a Validate call, in field order.
+	Errors []FieldError
+)
+
+// Error returns the message.
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// Error returns the messages, one per line.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// translate converts validator.ValidationErrors to Errors; other errors
+// (e.g. for a non-struct value) are returned as is.
+func translate(err error, language Language) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	errs := make(Errors, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		path := fieldErr.StructNamespace()
+		if _, after, found := strings.Cut(path, "."); found {
+			path = after
+		}
+
+		errs[i] = FieldError{
+			Path:    path,
+			Tag:     fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Value:   fieldErr.Value(),
+			Message: message(language, fieldErr.Tag(), fieldErr.Kind(), path, fieldErr.Param(), fieldErr.Value()),
+		}
+	}
+
+	return errs
+}
+
+// kindSuffix returns the message variant of a field kind: ".string" for
+// lengths in characters, ".items" for collections, "" for anything else.
+func kindSuffix(kind reflect.Kind) string {
+	switch kind { //nolint:exhaustive
+	case reflect.String:
+		return ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return ".items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type (
+	// Language is a language tag of the error messages, e.g. "pt-BR".
+	Language string
+)
+
+const (
+	// English messages (the default).
+	English Language = "en"
+	// PortugueseBR messages (Brazilian Portuguese).
+	PortugueseBR Language = "pt-BR"
+
+	// fallbackKey is the message used for tags without one.
+	fallbackKey = "*"
+)
+
+var (
+	messagesLock    sync.RWMutex
+	defaultLanguage = English
+
+	// messages holds the message templates by language and tag; a tag can
+	// have ".string" (lengths) and ".items" (collections) variants.
+	messages = map[Language]map[string]string{
+		English: {
+			fallbackKey:     "{field} failed on the '{tag}' rule",
+			"required":      "{field} is required",
+			"required_if":   "{field} is required when {param}",
+			"required_with": "{field} is required when {param} is set",
+			"min":           "{field} must be at least {param}",
+			"min.string":    "{field} must have at least {param} characters",
+			"min.items":     "{field} must have at least {param} items",
+			"max":           "{field} must be at most {param}",
+			"max.string":    "{field} must have at most {param} characters",
+			"max.items":     "{field} must have at most {param} items",
+			"len":           "{field} must be {param}",
+			"len.string":    "{field} must have {param} characters",
+			"len.items":     "{field} must have {param} items",
+			"eq":            "{field} must be {param}",
+			"ne":            "{field} must not be {param}",
+			"gt":            "{field} must be greater than {param}",
+			"gte":           "{field} must be at least {param}",
+			"lt":            "{field} must be less than {param}",
+			"lte":           "{field} must be at most {param}",
+			"oneof":         "{field} must be one of [{param}]",
+			"email":         "{field} must be an email address",
+			"url":           "{field} must be a URL",
+			"uri":           "{field} must be a URI",
+			"hostname":      "{field} must be a hostname",
+			"ip":            "{field} must be an IP address",
+			"cidr":          "{field} must be a CIDR network",
+			"uuid":          "{field} must be a UUID",
+			"file":          "{field} must be an existing file",
+			"dir":           "{field} must be an existing directory",
+			"eqfield":       "{field} must be equal to {param}",
+			"nefield":       "{field} must be different from {param}",
+			"gtfield":       "{field} must be greater than {param}",
+			"ltfield":       "{field} must be less than {param}",
+			"cpf":           "{field} must be a valid CPF",
+			"cnpj":          "{field} must be a valid CNPJ",
+		},
+		PortugueseBR: {
+			fallbackKey:     "{field} falhou na regra '{tag}'",
+			"required":      "{field} é obrigatório",
+			"required_if":   "{field} é obrigatório quando {param}",
+			"required_with": "{field} é obrigatório quando {param} estiver preenchido",
+			"min":           "{field} deve ser no mínimo {param}",
+			"min.string":    "{field} deve ter no mínimo {param} caracteres",
+			"min.items":     "{field} deve ter no mínimo {param} itens",
+			"max":           "{field} deve ser no máximo {param}",
+			"max.string":    "{field} deve ter no máximo {param} caracteres",
+			"max.items":     "{field} deve ter no máximo {param} itens",
+			"len":           "{field} deve ser {param}",
+			"len.string":    "{field} deve ter {param} caracteres",
+			"len.items":     "{field} deve ter {param} itens",
+			"eq":            "{field} deve ser {param}",
+			"ne":            "{field} não pode ser {param}",
+			"gt":            "{field} deve ser maior que {param}",
+			"gte":           "{field} deve ser no mínimo {param}",
+			"lt":            "{field} deve ser menor que {param}",
+			"lte":           "{field} deve ser no máximo {param}",
+			"oneof":         "{field} deve ser um de [{param}]",
+			"email":         "{field} deve ser um endereço de e-mail",
+			"url":           "{field} deve ser uma URL",
+			"uri":           "{field} deve ser uma URI",
+			"hostname":      "{field} deve ser um nome de host",
+			"ip":            "{field} deve ser um endereço IP",
+			"cidr":          "{field} deve ser uma rede CIDR",
+			"uuid":          "{field} deve ser um UUID",
+			"file":          "{field} deve ser um arquivo existente",
+			"dir":           "{field} deve ser um diretório existente",
+			"eqfield":       "{field} deve ser igual a {param}",
+			"nefield":       "{field} deve ser diferente de {param}",
+			"gtfield":       "{field} deve ser maior que {param}",
+			"ltfield":       "{field} deve ser menor que {param}",
+			"cpf":           "{field} deve ser um CPF válido",
+			"cnpj":          "{field} deve ser um CNPJ válido",
+		},
+	}
+)
+
+// SetDefaultLanguage sets the language of the messages of Validate calls
+// without WithLanguage. Languages without a message for a tag fall back
+// to English.
+func SetDefaultLanguage(language Language) {
+	messagesLock.Lock()
+	defer messagesLock.Unlock()
+
+	defaultLanguage = language
+}
+
+// DefaultLanguage returns the language set by SetDefaultLanguage.
+func DefaultLanguage() Language {
+	messagesLock.RLock()
+	defer messagesLock.RUnlock()
+
+	return defaultLanguage
+}
+
+// RegisterMessage sets the message of tag in language, adding the
+// language if needed. The template placeholders are {field} (the field
+// path), {param}, {value} and {tag}; "min.string" and "min.items" keys set
+// the message for string lengths and collection sizes.
+func RegisterMessage(language Language, tag, message string) {
+	messagesLock.Lock()
+	defer messagesLock.Unlock()
+
+	if messages[language] == nil {
+		messages[language] = map[string]string{}
+	}
+
+	messages[language][tag] = message
+}
+
+// message renders the message of a failed tag rule.
+func message(language Language, tag string, kind reflect.Kind, field, param string, value any) string {
+	messagesLock.RLock()
+	template := lookupMessage(language, tag, kindSuffix(kind))
+	messagesLock.RUnlock()
+
+	return strings.NewReplacer(
+		"{field}", field,
+		"{param}", param,
+		"{value}", fmt.Sprint(value),
+		"{tag}", tag,
+	).Replace(template)
+}
+
+// lookupMessage returns the most specific template of tag: the language
+// before English, the kind variant before the plain tag.
+// Caller MUST hold messagesLock.
+func lookupMessage(language Language, tag, suffix string) string {
+	for _, lang := range []Language{language, English} {
+		for _, key := range []string{tag + suffix, tag} {
+			if template, ok := messages[lang][key]; ok {
+				return template
+			}
+		}
+	}
+
+	if template, ok := messages[language][fallbackKey]; ok {
+		return template
+	}
+
+	return messages[English][fallbackKey]
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type (
+	// FieldLevel gives a tag rule access to the field being validated, its
+	// parent struct and the tag parameter (validator.FieldLevel).
+	FieldLevel = validator.FieldLevel
+
+	// StructReporter reports the problems found by a struct rule.
+	StructReporter interface {
+		// Report flags the field (the Go field name, e.g. "EndDate") as
+		// failing tag with param; the message is looked up like the
+		// messages of tag rules.
+		Report(field, tag, param string)
+	}
+
+	structReporter struct {
+		level validator.StructLevel
+	}
+)
+
+// RegisterTag registers a custom tag rule usable in `validate` tags, with
+// its English message (see RegisterMessage for the placeholders and other
+// languages). Register rules at program start, before validating.
+//
+//	validation.RegisterTag("cron", func(fl validation.FieldLevel) bool {
+//	    _, err := cron.ParseStandard(fl.Field().String())
+//	    return err == nil
+//	}, "{field} must be a cron expression")
+func RegisterTag(tag string, rule func(fl FieldLevel) bool, message string) error {
+	if err := getValidator().RegisterValidation(tag, rule); err != nil {
+		return fmt.Errorf("config/validation: %w", err)
+	}
+
+	if message != "" {
+		RegisterMessage(English, tag, message)
+	}
+
+	return nil
+}
+
+// RegisterAlias registers tag as a shorthand for a list of rules, e.g.
+// RegisterAlias("port", "min=1,max=65535"). Failures are reported with
+// the message of the alias.
+func RegisterAlias(tag, rules, message string) {
+	getValidator().RegisterAlias(tag, rules)
+
+	if message != "" {
+		RegisterMessage(English, tag, message)
+	}
+}
+
+// RegisterStructRule registers a cross-field rule for the struct type T,
+// run after its field rules. Register rules at program start.
+//
+//	validation.RegisterStructRule(func(w Window, r validation.StructReporter) {
+//	    if w.End.Before(w.Start) {
+//	        r.Report("End", "gtfield", "Start")
+//	    }
+//	})
+func RegisterStructRule[T any](rule func(v T, reporter StructReporter)) {
+	var zero T
+
+	getValidator().RegisterStructValidation(func(level validator.StructLevel) {
+		v, ok := level.Current().Interface().(T)
+		if ok {
+			rule(v, structReporter{level: level})
+		}
+	}, zero)
+}
+
+// Report implements StructReporter.
+func (r structReporter) Report(field, tag, param string) {
+	var value any
+	if fieldValue := r.level.Current().FieldByName(field); fieldValue.IsValid() && fieldValue.CanInterface() {
+		value = fieldValue.Interface()
+	}
+
+	r.level.ReportError(value, field, field, tag, param)
+}
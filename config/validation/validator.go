@@ -1,17 +1,28 @@
 package validation
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/go-playground/validator/v10"
+
+	brdocs "github.com/guionardo/go/br_docs"
 )
 
-// Validator is the interface implemented by types that can self-validate.
-// Types implementing this interface receive custom validation logic
-// before the standard struct validation runs.
-type Validator interface {
-	Validate() error
-}
+type (
+	// Validator is the interface implemented by types that can self-validate.
+	// Its result is combined with the struct tag rules by Validate.
+	Validator interface {
+		Validate() error
+	}
+
+	// Option configures a Validate call.
+	Option func(*options)
+
+	options struct {
+		language Language
+	}
+)
 
 var (
 	validateOnce sync.Once
@@ -21,18 +32,50 @@ var (
 func getValidator() *validator.Validate {
 	validateOnce.Do(func() {
 		validate = validator.New(validator.WithRequiredStructEnabled())
+
+		for tag, isValid := range map[string]func(string) bool{"cpf": brdocs.IsCPF, "cnpj": brdocs.IsCNPJ} {
+			_ = validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+				return isValid(fl.Field().String())
+			})
+		}
 	})
 
 	return validate
 }
 
-// Validate tries to validate a struct using validator_v10 or the inner Validate method, if declared
-// If the struct implements a method Validate() error, it will be used. Otherwise, the validator/v10
-// validate.Struct(v) will be used
-func Validate(v any) error {
+// WithLanguage sets the language of the error messages of this call
+// (see SetDefaultLanguage).
+func WithLanguage(language Language) Option {
+	return func(o *options) {
+		o.language = language
+	}
+}
+
+// Validate validates a struct with both its Validate method, if it
+// implements Validator, and its `validate` struct tags (go-playground
+// validator rules plus the registered ones), and returns all the problems
+// joined. Tag rule failures are returned as Errors, with readable
+// per-field messages:
+//
+//	var fieldErrs validation.Errors
+//	if errors.As(err, &fieldErrs) {
+//	    for _, fe := range fieldErrs {
+//	        fmt.Println(fe.Path, fe.Message) // Database.Host is required
+//	    }
+//	}
+func Validate(v any, opts ...Option) error {
+	o := options{language: DefaultLanguage()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var errs []error
+
 	if validator, ok := v.(Validator); ok {
-		return validator.Validate()
+		errs = append(errs, validator.Validate())
 	}
 
-	return getValidator().Struct(v)
+	errs = append(errs, translate(getValidator().Struct(v), o.language))
+
+	return errors.Join(errs...)
 }
@@ -69,3 +69,130 @@ func TestValidate(t *testing.T) {
 		require.Error(t, validation.Validate(s))
 	})
 }
+
+type (
+	aggregatedConfig struct {
+		Name string `validate:"required"`
+	}
+
+	messagesConfig struct {
+		Name     string   `validate:"required"`
+		Tags     []string `validate:"min=2"`
+		Port     int      `validate:"min=1"`
+		Code     string   `validate:"len=3"`
+		Document string   `validate:"cpf"`
+		Database struct {
+			Host string `validate:"hostname"`
+		}
+	}
+
+	customTagConfig struct {
+		Schedule string `validate:"even_length"`
+		Port     int    `validate:"tcp_port"`
+	}
+
+	window struct {
+		Start int
+		End   int
+	}
+)
+
+func (aggregatedConfig) Validate() error {
+	return errors.New("custom rule failed")
+}
+
+func TestValidateAggregates(t *testing.T) {
+	t.Parallel()
+
+	err := validation.Validate(aggregatedConfig{})
+	require.ErrorContains(t, err, "custom rule failed")
+	require.ErrorContains(t, err, "Name is required")
+}
+
+func TestValidateMessages(t *testing.T) {
+	t.Parallel()
+
+	v := messagesConfig{Tags: []string{"a"}, Code: "ab", Document: "111.111.111-11"}
+	v.Database.Host = "bad host!"
+
+	t.Run("english", func(t *testing.T) {
+		t.Parallel()
+
+		var errs validation.Errors
+		require.ErrorAs(t, validation.Validate(v), &errs)
+
+		require.Equal(t, []string{
+			"Name is required",
+			"Tags must have at least 2 items",
+			"Port must be at least 1",
+			"Code must have 3 characters",
+			"Document must be a valid CPF",
+			"Database.Host must be a hostname",
+		}, messagesOf(errs))
+		require.Equal(t, validation.FieldError{
+			Path: "Tags", Tag: "min", Param: "2", Value: []string{"a"}, Message: "Tags must have at least 2 items",
+		}, errs[1])
+	})
+
+	t.Run("portuguese", func(t *testing.T) {
+		t.Parallel()
+
+		var errs validation.Errors
+		require.ErrorAs(t, validation.Validate(v, validation.WithLanguage(validation.PortugueseBR)), &errs)
+		require.Equal(t, "Name é obrigatório", errs[0].Message)
+		require.Equal(t, "Tags deve ter no mínimo 2 itens", errs[1].Message)
+		require.Equal(t, "Document deve ser um CPF válido", errs[4].Message)
+	})
+
+	t.Run("unknown_language_falls_back_to_english", func(t *testing.T) {
+		t.Parallel()
+
+		var errs validation.Errors
+		require.ErrorAs(t, validation.Validate(v, validation.WithLanguage("fr")), &errs)
+		require.Equal(t, "Name is required", errs[0].Message)
+	})
+}
+
+func TestRegisterTag(t *testing.T) { //nolint:paralleltest // registers rules on the shared validator
+	require.NoError(t, validation.RegisterTag("even_length", func(fl validation.FieldLevel) bool {
+		return len(fl.Field().String())%2 == 0
+	}, "{field} must have an even length, got {value}"))
+	validation.RegisterMessage(validation.PortugueseBR, "even_length", "{field} deve ter tamanho par")
+	validation.RegisterAlias("tcp_port", "min=1,max=65535", "{field} must be a TCP port")
+	require.Error(t, validation.RegisterTag("", nil, ""))
+
+	var errs validation.Errors
+	require.ErrorAs(t, validation.Validate(customTagConfig{Schedule: "odd", Port: 70000}), &errs)
+	require.Equal(t, []string{"Schedule must have an even length, got odd", "Port must be a TCP port"}, messagesOf(errs))
+
+	require.ErrorAs(t, validation.Validate(customTagConfig{Schedule: "odd", Port: 80},
+		validation.WithLanguage(validation.PortugueseBR)), &errs)
+	require.Equal(t, []string{"Schedule deve ter tamanho par"}, messagesOf(errs))
+
+	require.NoError(t, validation.Validate(customTagConfig{Schedule: "even", Port: 80}))
+}
+
+func TestRegisterStructRule(t *testing.T) { //nolint:paralleltest // registers rules on the shared validator
+	validation.RegisterStructRule(func(w window, r validation.StructReporter) {
+		if w.End < w.Start {
+			r.Report("End", "gtfield", "Start")
+		}
+	})
+
+	var errs validation.Errors
+	require.ErrorAs(t, validation.Validate(window{Start: 2, End: 1}), &errs)
+	require.Equal(t, validation.FieldError{
+		Path: "End", Tag: "gtfield", Param: "Start", Value: 1, Message: "End must be greater than Start",
+	}, errs[0])
+
+	require.NoError(t, validation.Validate(window{Start: 1, End: 2}))
+}
+
+func messagesOf(errs validation.Errors) []string {
+	messages := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		messages[i] = fieldErr.Message
+	}
+
+	return messages
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/guionardo/go/config/source"
+)
+
+// Change carries the configuration before and after a replacement.
+type Change[T any] struct {
+	Old T
+	New T
+}
+
+// watchBuffer is the number of pending changes a watcher channel can hold
+// before further changes are dropped for that watcher.
+const watchBuffer = 8
+
+// WithWatchInterval sets how often Watch polls the profile files (and the
+// versioned sources, such as source.HTTP) for changes.
+// Defaults to DefaultWatchInterval.
+func WithWatchInterval(interval time.Duration) providerOption {
+	return func(p *provider) {
+		p.watchInterval = interval
+	}
+}
+
+// Watch enables hot-reload: the profile directory is polled for changes to
+// the scope and default files (and anything else under it), as are the
+// source.VersionedSource sources (e.g. a remote source.HTTP). On change, the
+// profiles are reloaded, merged with the environment and validated; a valid
+// result atomically replaces the current configuration, an invalid one is
+// logged and the current configuration is kept.
+//
+// The returned channel receives every configuration change (reloads and
+// UpdateConfiguration calls) until ctx is done, when it is closed. A single
+// poll loop is shared by all watchers and stops with the last one.
+// Changes are dropped for a watcher whose buffer is full.
+func (p *Provider[T]) Watch(ctx context.Context) <-chan Change[T] {
+	ch := make(chan Change[T], watchBuffer)
+
+	p.watchLock.Lock()
+	if p.watchers == nil {
+		p.watchers = make(map[chan Change[T]]struct{})
+	}
+
+	p.watchers[ch] = struct{}{}
+
+	if p.stopWatch == nil {
+		loopCtx, cancel := context.WithCancel(context.Background())
+		p.stopWatch = cancel
+
+		go p.watchLoop(loopCtx, p.watchFingerprint())
+	}
+	p.watchLock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.unwatch(ch)
+	}()
+
+	return ch
+}
+
+func (p *Provider[T]) unwatch(ch chan Change[T]) {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+
+	delete(p.watchers, ch)
+	close(ch)
+
+	if len(p.watchers) == 0 && p.stopWatch != nil {
+		p.stopWatch()
+		p.stopWatch = nil
+	}
+}
+
+// watchLoop polls the watch fingerprint and reloads on change.
+func (p *Provider[T]) watchLoop(ctx context.Context, fingerprint string) {
+	interval := p.watchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := p.watchFingerprint()
+			if current == fingerprint {
+				continue
+			}
+
+			fingerprint = current
+			p.log().Info("configuration sources changed, reloading configuration")
+
+			_, _ = p.reload(ctx)
+		}
+	}
+}
+
+// reload reads the profiles and environment again and swaps the
+// configuration if it is valid, returning the current configuration.
+// Profile read errors are fatal here, so a file caught mid-write does not
+// replace the configuration with defaults.
+func (p *Provider[T]) reload(ctx context.Context) (T, error) {
+	configuration, meta, err := p.readConfiguration(ctx, true)
+	if err != nil {
+		p.log().Error("error reloading configuration, keeping current", "error", err)
+		return p.current(), err
+	}
+
+	p.lock.Lock()
+	old := p.configuration
+	changed, err := p.updateConfiguration(configuration, meta, SnapshotReload)
+	current := p.configuration
+	p.lock.Unlock()
+
+	if err != nil {
+		p.log().Error("reloaded configuration is invalid, keeping current", "error", err)
+		return current, err
+	}
+
+	if changed {
+		p.notify(Change[T]{Old: old, New: configuration}, meta.secretPaths)
+	}
+
+	return current, nil
+}
+
+// watchFingerprint combines the profiles fingerprint with the versions of
+// the versioned sources. A source failing to report its version keeps its
+// last version, so an outage alone does not trigger a reload.
+func (p *provider) watchFingerprint() string {
+	fingerprint := p.profilesFingerprint()
+
+	for _, layer := range p.sources {
+		versioned, ok := layer.(source.VersionedSource)
+		if !ok {
+			continue
+		}
+
+		version, err := versioned.Version()
+		if err != nil {
+			p.log().Warn("error polling configuration source", "source", layer.Name(), "error", err)
+		}
+
+		fingerprint += "\x00" + version
+	}
+
+	return fingerprint
+}
+
+// profilesFingerprint hashes the names and contents of every regular file
+// under the profiles path. Unreadable entries are skipped; an empty or
+// missing directory yields a stable fingerprint.
+func (p *provider) profilesFingerprint() string {
+	hash := sha256.New()
+
+	root := p.getProfilesPath()
+	if root == "" {
+		return ""
+	}
+
+	_ = filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil //nolint:nilerr // best effort: skip unreadable entries
+		}
+
+		file, err := os.Open(filepath.Clean(name))
+		if err != nil {
+			return nil //nolint:nilerr // best effort: skip unreadable entries
+		}
+		defer file.Close() //nolint: errcheck
+
+		_, _ = io.WriteString(hash, name+"\x00")
+		_, _ = io.Copy(hash, file)
+
+		return nil
+	})
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
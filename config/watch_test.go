@@ -0,0 +1,186 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/guionardo/go/config/source"
+	httptestmock "github.com/guionardo/go/httptest_mock"
+)
+
+func newWatchedProvider(t *testing.T, content string) (*Provider[testConfig], string) {
+	t.Helper()
+
+	tmp := t.TempDir()
+	profilePath := path.Join(tmp, "default.yml")
+	require.NoError(t, os.WriteFile(profilePath, []byte(content), 0600))
+
+	provider := NewProvider[testConfig](
+		WithProfilesPath(tmp),
+		WithDefaultScope("default"),
+		WithScope("default"),
+		WithWatchInterval(5*time.Millisecond),
+	)
+
+	_, err := provider.GetConfiguration()
+	require.NoError(t, err)
+
+	return provider, profilePath
+}
+
+func writeProfile(t *testing.T, profilePath, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(profilePath, []byte(content), 0600))
+}
+
+func receiveChange(t *testing.T, ch <-chan Change[testConfig]) Change[testConfig] {
+	t.Helper()
+
+	select {
+	case change := <-ch:
+		return change
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for configuration change")
+
+		return Change[testConfig]{}
+	}
+}
+
+func TestProviderWatch(t *testing.T) {
+	t.Run("reloads_on_profile_change", func(t *testing.T) {
+		provider, profilePath := newWatchedProvider(t, "name: first\nversion: 1")
+
+		changes := provider.Watch(t.Context())
+		require.NoError(t, os.WriteFile(profilePath, []byte("name: second\nversion: 2"), 0600))
+
+		change := receiveChange(t, changes)
+		assert.Equal(t, "first", change.Old.Name)
+		assert.Equal(t, "second", change.New.Name)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, 2, cfg.Version)
+	})
+
+	t.Run("reloads_on_http_source_change", func(t *testing.T) {
+		var (
+			lock sync.Mutex
+			body = "version: 1"
+		)
+
+		server, _ := httptestmock.SetupServer(t, httptestmock.WithoutLog(), httptestmock.WithRequests(
+			httptestmock.NewMock(http.MethodGet, "/config").WithResponseStatus(http.StatusOK).WithCustomHandler(
+				func(_ httptestmock.Mocker, w http.ResponseWriter, _ *http.Request) {
+					lock.Lock()
+					defer lock.Unlock()
+
+					_, _ = w.Write([]byte(body))
+				}),
+		))
+
+		tmp := t.TempDir()
+		writeProfile(t, path.Join(tmp, "default.yml"), "name: remote")
+
+		provider := NewProvider[testConfig](
+			WithProfilesPath(tmp),
+			WithDefaultScope("default"),
+			WithScope("default"),
+			WithSources(source.HTTP(server.URL+"/config")),
+			WithWatchInterval(5*time.Millisecond),
+		)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, 1, cfg.Version)
+
+		changes := provider.Watch(t.Context())
+
+		lock.Lock()
+		body = "version: 2"
+		lock.Unlock()
+
+		change := receiveChange(t, changes)
+		assert.Equal(t, 1, change.Old.Version)
+		assert.Equal(t, 2, change.New.Version)
+	})
+
+	t.Run("keeps_current_on_invalid_reload", func(t *testing.T) {
+		provider, profilePath := newWatchedProvider(t, "name: valid")
+
+		changes := provider.Watch(t.Context())
+		require.NoError(t, os.WriteFile(profilePath, []byte("version: 3"), 0600))
+
+		// Wait until the invalid content was seen, then fix it.
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, os.WriteFile(profilePath, []byte("name: fixed"), 0600))
+
+		change := receiveChange(t, changes)
+		assert.Equal(t, "valid", change.Old.Name)
+		assert.Equal(t, "fixed", change.New.Name)
+	})
+
+	t.Run("keeps_current_on_invalid_yaml", func(t *testing.T) {
+		provider, profilePath := newWatchedProvider(t, "name: valid")
+
+		require.NoError(t, os.WriteFile(profilePath, []byte(":::: invalid ::::"), 0600))
+		_, err := provider.reload(t.Context())
+		require.Error(t, err)
+
+		cfg, err := provider.GetConfiguration()
+		require.NoError(t, err)
+		assert.Equal(t, "valid", cfg.Name)
+	})
+
+	t.Run("update_configuration_notifies", func(t *testing.T) {
+		provider, _ := newWatchedProvider(t, "name: first")
+
+		changes := provider.Watch(t.Context())
+		require.NoError(t, provider.UpdateConfiguration(testConfig{Name: "updated"}))
+
+		change := receiveChange(t, changes)
+		assert.Equal(t, "first", change.Old.Name)
+		assert.Equal(t, "updated", change.New.Name)
+	})
+
+	t.Run("cancel_closes_channel_and_stops_loop", func(t *testing.T) {
+		provider, _ := newWatchedProvider(t, "name: first")
+
+		ctx, cancel := context.WithCancel(t.Context())
+		changes := provider.Watch(ctx)
+		cancel()
+
+		_, open := <-changes
+		assert.False(t, open)
+
+		provider.watchLock.Lock()
+		defer provider.watchLock.Unlock()
+		assert.Empty(t, provider.watchers)
+		assert.Nil(t, provider.stopWatch)
+	})
+}
+
+func TestProfilesFingerprint(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	p := &provider{profilesPath: tmp}
+
+	empty := p.profilesFingerprint()
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"), []byte("a: 1"), 0600))
+
+	first := p.profilesFingerprint()
+	assert.NotEqual(t, empty, first)
+	assert.Equal(t, first, p.profilesFingerprint())
+
+	require.NoError(t, os.WriteFile(path.Join(tmp, "default.yml"), []byte("a: 2"), 0600))
+	assert.NotEqual(t, first, p.profilesFingerprint())
+}